@@ -0,0 +1,109 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"log"
+)
+
+// batchPageSize is how many votes eachTally asks its source for at a
+// time.
+const batchPageSize = 50
+
+// eachTally tallies every vote in the source matching the query (whose
+// pagination is ignored), examining at most limit votes if limit is
+// positive, and passes each to fn.  Sources that implement
+// source.VoteIterator are walked through in one go, and anything else
+// is listed a page at a time.  Votes that the source lists without
+// their voters are looked up individually, and skipped if they still
+// have none or turn out to be malformed.
+func eachTally(
+	votes source.VoteSource,
+	query sunlight.VoteQuery,
+	options Options,
+	limit int,
+	fn func(vote sunlight.Vote, tally Tally),
+) error {
+	examined := 0
+	visit := func(vote sunlight.Vote) (bool, error) {
+		if limit > 0 && examined == limit {
+			return false, nil
+		}
+		examined++
+
+		if len(vote.Voters) == 0 {
+			rollID := vote.RollID
+			var err error
+			vote, err = votes.GetVote(rollID)
+			if _, ok := err.(*sunlight.MalformedVoteError); ok {
+				log.Printf("Skipping vote %s: %v", rollID, err)
+				return true, nil
+			} else if err != nil {
+				return false, err
+			}
+			if len(vote.Voters) == 0 {
+				return true, nil
+			}
+		}
+
+		tally, err := NewTally(vote, options)
+		if err != nil {
+			return false, err
+		}
+		fn(vote, tally)
+		return true, nil
+	}
+
+	if iterator, ok := votes.(source.VoteIterator); ok {
+		var visitErr error
+		err := iterator.EachVote(query, func(vote sunlight.Vote) bool {
+			var more bool
+			more, visitErr = visit(vote)
+			return more
+		})
+		if err != nil {
+			return err
+		}
+		return visitErr
+	}
+
+	query.PerPage = batchPageSize
+	for query.Page = 1; ; query.Page++ {
+		page, err := votes.ListVotes(query)
+		if err != nil {
+			return err
+		}
+
+		for _, vote := range page {
+			more, err := visit(vote)
+			if err != nil {
+				return err
+			} else if !more {
+				return nil
+			}
+		}
+
+		if len(page) < batchPageSize || (limit > 0 && examined == limit) {
+			return nil
+		}
+	}
+}
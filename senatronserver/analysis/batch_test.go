@@ -0,0 +1,126 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pagedSource hides a source's EachVote, so eachTally has to page
+// through it, and lists votes without their voters the way the remote
+// APIs do.
+type pagedSource struct {
+	source.VoteSource
+	gets int
+}
+
+func (p *pagedSource) ListVotes(
+	query sunlight.VoteQuery,
+) ([]sunlight.Vote, error) {
+	votes, err := p.VoteSource.ListVotes(query)
+	summaries := make([]sunlight.Vote, len(votes))
+	for i, vote := range votes {
+		vote.Voters = nil
+		summaries[i] = vote
+	}
+	return summaries, err
+}
+
+func (p *pagedSource) GetVote(rollID string) (sunlight.Vote, error) {
+	p.gets++
+	return p.VoteSource.GetVote(rollID)
+}
+
+// batchSource returns a Memory source holding count senate votes, one
+// a day, each with a single senator from California voting yea.
+func batchSource(count int) *source.Memory {
+	memory := source.NewMemory()
+	start := time.Date(2015, time.January, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		memory.AddVote(sunlight.Vote{
+			RollID:   fmt.Sprintf("s%d-2015", i+1),
+			Chamber:  sunlight.Senate,
+			VotedAt:  start.AddDate(0, 0, i),
+			Required: "1/2",
+			Voters: map[string]sunlight.Voter{
+				"F000062": {
+					Vote: sunlight.Yes,
+					Info: sunlight.VoterInfo{
+						BioguideID: "F000062",
+						State:      "CA",
+						Party:      "D",
+					},
+				},
+			},
+		})
+	}
+	return memory
+}
+
+func TestEachTallyIteratesOrPages(t *testing.T) {
+	memory := batchSource(2*batchPageSize + 10)
+
+	for _, limit := range []int{0, 30, batchPageSize, 2*batchPageSize + 10, 500} {
+		collect := func(votes source.VoteSource) []string {
+			rollIDs := []string{}
+			err := eachTally(
+				votes,
+				sunlight.VoteQuery{},
+				Options{},
+				limit,
+				func(vote sunlight.Vote, tally Tally) {
+					if tally.SenateTotal != 1 {
+						t.Errorf("%s: Tallied %d votes", vote.RollID, tally.SenateTotal)
+					}
+					rollIDs = append(rollIDs, vote.RollID)
+				},
+			)
+			if err != nil {
+				t.Fatalf("limit %d: %v", limit, err)
+			}
+			return rollIDs
+		}
+
+		iterated := collect(memory)
+		paged := &pagedSource{VoteSource: memory}
+		fromPages := collect(paged)
+
+		want := 2*batchPageSize + 10
+		if limit > 0 && limit < want {
+			want = limit
+		}
+		if len(iterated) != want {
+			t.Errorf("limit %d: Iterated over %d votes, want %d", limit, len(iterated), want)
+		}
+		if !reflect.DeepEqual(iterated, fromPages) {
+			t.Errorf("limit %d: Iterated %v, paged %v", limit, iterated, fromPages)
+		}
+		if paged.gets != want {
+			t.Errorf("limit %d: Looked up %d votes, want %d", limit, paged.gets, want)
+		}
+		if len(iterated) > 0 && iterated[0] != fmt.Sprintf("s%d-2015", 2*batchPageSize+10) {
+			t.Errorf("limit %d: Started with %s, not the most recent vote", limit, iterated[0])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"errors"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"strings"
+)
+
+// ErrNoPassageVotes is returned by NewBillComparison when neither
+// chamber has a passage vote on record for the bill.
+var ErrNoPassageVotes = errors.New("analysis: No passage votes for that bill")
+
+// BillComparison sets a bill's passage votes in each chamber side by
+// side.  Either chamber's vote is nil if it has none on record.
+type BillComparison struct {
+	BillID string          `json:"bill_id"`
+	Senate *ChamberPassage `json:"senate"`
+	House  *ChamberPassage `json:"house"`
+}
+
+// ChamberPassage is the outcome of a bill's passage vote in a single
+// chamber, along with its full tally.
+type ChamberPassage struct {
+	Divergence
+	Tally Tally `json:"tally"`
+}
+
+// passageQuestions are the phrases that mark a vote as one on passing
+// a bill (or its final form) outright, rather than on amendments,
+// motions and the like.
+var passageQuestions = []string{
+	"passage",
+	"conference report",
+	"concur",
+	"agreeing to the resolution",
+	"veto",
+}
+
+// IsPassage reports whether a vote was on passing a bill outright.
+func IsPassage(vote sunlight.Vote) bool {
+	question := strings.ToLower(vote.RollType + " " + vote.Question)
+	for _, phrase := range passageQuestions {
+		if strings.Contains(question, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBillComparison finds the most recent passage vote on the given
+// bill in each chamber and tallies them both.  It returns
+// ErrNoPassageVotes if it can't find one in either.
+func NewBillComparison(
+	votes source.VoteSource,
+	billID string,
+	options Options,
+) (BillComparison, error) {
+	comparison := BillComparison{BillID: billID}
+
+	for _, chamber := range []string{sunlight.Senate, sunlight.House} {
+		passage, err := findPassage(votes, billID, chamber, options)
+		if err != nil {
+			return BillComparison{}, err
+		}
+
+		if chamber == sunlight.Senate {
+			comparison.Senate = passage
+		} else {
+			comparison.House = passage
+		}
+	}
+
+	if comparison.Senate == nil && comparison.House == nil {
+		return BillComparison{}, ErrNoPassageVotes
+	}
+	return comparison, nil
+}
+
+// findPassage tallies the most recent passage vote on a bill in the
+// given chamber, returning nil if there isn't one.
+func findPassage(
+	votes source.VoteSource,
+	billID string,
+	chamber string,
+	options Options,
+) (*ChamberPassage, error) {
+	query := sunlight.VoteQuery{
+		BillID:  billID,
+		Chamber: chamber,
+		PerPage: batchPageSize,
+	}
+
+	for query.Page = 1; ; query.Page++ {
+		page, err := votes.ListVotes(query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vote := range page {
+			if !IsPassage(vote) {
+				continue
+			}
+
+			if len(vote.Voters) == 0 {
+				vote, err = votes.GetVote(vote.RollID)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			tally, err := NewTally(vote, options)
+			if err != nil {
+				return nil, err
+			}
+			return &ChamberPassage{
+				Divergence: NewDivergence(vote, tally),
+				Tally:      tally,
+			}, nil
+		}
+
+		if len(page) < batchPageSize {
+			return nil, nil
+		}
+	}
+}
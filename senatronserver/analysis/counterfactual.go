@@ -0,0 +1,155 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+)
+
+// ErrUnknownVoter is returned by Counterfactual.Apply when asked to
+// change the vote of somebody who didn't vote.
+var ErrUnknownVoter = errors.New("analysis: No such voter in the vote")
+
+// ErrUnknownState is returned by Counterfactual.Apply when asked to
+// replace the delegation of a state without any seats.
+var ErrUnknownState = errors.New("analysis: No seats for that state")
+
+// ErrDelegationSize is returned by Counterfactual.Apply when a
+// replacement delegation has more members than its state has seats.
+var ErrDelegationSize = errors.New(
+	"analysis: Delegation larger than its state's seats",
+)
+
+// ErrUnknownDistrict is returned by Counterfactual.Apply when a member
+// of a replacement House delegation isn't from one of their state's
+// districts.
+var ErrUnknownDistrict = errors.New("analysis: No such district")
+
+// ErrInvalidPosition is returned by Counterfactual.Apply when a
+// changed vote isn't one of sunlight.Positions.
+var ErrInvalidPosition = errors.New("analysis: Invalid vote position")
+
+// Counterfactual describes changes to a vote, to see how its outcome
+// would have differed had some members voted differently or some
+// states sent different members.
+type Counterfactual struct {
+	// Votes maps the bioguide IDs of members to the positions they
+	// take instead of the ones they actually did.
+	Votes map[string]sunlight.Position `json:"votes"`
+
+	// Delegations replaces the delegation of each state given with the
+	// listed members, whose states are filled in to match.  Members
+	// without bioguide IDs are fine, and an empty delegation leaves
+	// all of a state's seats vacant.  In the House, each member's
+	// district has to be one of the state's (0 for at-large seats).
+	Delegations map[string][]sunlight.Voter `json:"delegations"`
+}
+
+// Apply returns a copy of the vote with the changes made to it.
+// Delegations are replaced first, so Votes can change the votes of
+// replacement members as well as original ones.
+func (c Counterfactual) Apply(vote sunlight.Vote) (sunlight.Vote, error) {
+	voters := make(map[string]sunlight.Voter, len(vote.Voters))
+	for id, voter := range vote.Voters {
+		if _, ok := c.Delegations[voter.Info.State]; ok {
+			continue
+		}
+		voters[id] = voter
+	}
+
+	for state, delegation := range c.Delegations {
+		seats, err := seatsAt(vote.Chamber, state, vote)
+		if err != nil {
+			return sunlight.Vote{}, ErrUnknownState
+		}
+		if len(delegation) > seats {
+			return sunlight.Vote{}, ErrDelegationSize
+		}
+		districts := map[int]bool{}
+		if vote.Chamber == sunlight.House {
+			numbers, err := census.Districts(state, votedAt(vote))
+			if err != nil {
+				return sunlight.Vote{}, ErrUnknownState
+			}
+			for _, district := range numbers {
+				districts[district] = true
+			}
+		}
+
+		for i, voter := range delegation {
+			if !validPosition(voter.Vote) {
+				return sunlight.Vote{}, ErrInvalidPosition
+			}
+			if vote.Chamber == sunlight.House &&
+				!districts[voter.Info.District] {
+				return sunlight.Vote{}, ErrUnknownDistrict
+			}
+			voter.Info.State = state
+
+			id := voter.Info.BioguideID
+			if id == "" {
+				id = fmt.Sprintf("%s-%d", state, i+1)
+			}
+			voters[id] = voter
+		}
+	}
+
+	for bioguideID, position := range c.Votes {
+		if !validPosition(position) {
+			return sunlight.Vote{}, ErrInvalidPosition
+		}
+		id, ok := voterKey(voters, bioguideID)
+		if !ok {
+			return sunlight.Vote{}, ErrUnknownVoter
+		}
+		voter := voters[id]
+		voter.Vote = position
+		voters[id] = voter
+	}
+
+	vote.Voters = voters
+	return vote, nil
+}
+
+// voterKey finds the key of the voter with the given bioguide ID,
+// which isn't always what voters are keyed by.
+func voterKey(
+	voters map[string]sunlight.Voter,
+	bioguideID string,
+) (string, bool) {
+	if _, ok := voters[bioguideID]; ok {
+		return bioguideID, true
+	}
+	for id, voter := range voters {
+		if voter.Info.BioguideID == bioguideID {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// validPosition reports whether the position is one of
+// sunlight.Positions.
+func validPosition(position sunlight.Position) bool {
+	return rank(position) < len(sunlight.Positions)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"math"
+	"sort"
+	"time"
+)
+
+// Divergence measures how far apart the senate and popular votes on a
+// single roll call were.
+type Divergence struct {
+	RollID   string    `json:"roll_id"`
+	VotedAt  time.Time `json:"voted_at"`
+	Question string    `json:"question"`
+	Result   string    `json:"result"`
+	Required string    `json:"required"`
+
+	// SenateYea and PopularYea are the percentages of the senate and
+	// popular votes cast either way that were cast for the question.
+	SenateYea  float64 `json:"senate_yea"`
+	PopularYea float64 `json:"popular_yea"`
+
+	// Gap is the difference between PopularYea and SenateYea, in
+	// percentage points.
+	Gap float64 `json:"gap"`
+
+	SenatePassed  bool `json:"senate_passed"`
+	PopularPassed bool `json:"popular_passed"`
+
+	// CounterMajoritarian flags votes where the senate and popular
+	// outcomes disagree.
+	CounterMajoritarian bool `json:"counter_majoritarian"`
+
+	// Summary describes both outcomes in words, as from
+	// Tally.Summary.
+	Summary string `json:"summary"`
+}
+
+// NewDivergence compares the senate and popular outcomes of a tallied
+// vote.
+func NewDivergence(vote sunlight.Vote, tally Tally) Divergence {
+	divergence := Divergence{
+		RollID:   vote.RollID,
+		VotedAt:  vote.VotedAt,
+		Question: vote.Question,
+		Result:   vote.Result,
+		Required: vote.Required,
+		SenateYea: percent(
+			float64(tally.SenateYeas()),
+			float64(tally.SenateNays()),
+		),
+		PopularYea:    percent(tally.PopularYeas(), tally.PopularNays()),
+		SenatePassed:  tally.SenatePassed(),
+		PopularPassed: tally.PopularPassed(),
+		Summary:       tally.Summary(),
+	}
+	divergence.Gap = divergence.PopularYea - divergence.SenateYea
+	divergence.CounterMajoritarian =
+		divergence.SenatePassed != divergence.PopularPassed
+	return divergence
+}
+
+// percent returns yeas as a percentage of yeas and nays together.
+func percent(yeas, nays float64) float64 {
+	if yeas+nays == 0 {
+		return 0
+	}
+	return yeas / (yeas + nays) * 100
+}
+
+// RankDivergences sorts divergences in place, counter-majoritarian
+// votes first and then by the size of the gap between the senate and
+// popular votes.
+func RankDivergences(divergences []Divergence) {
+	sort.Stable(byDivergence(divergences))
+}
+
+// FindDivergences runs the senate and popular comparison over every
+// vote in the source matching the query (whose pagination is
+// ignored), examining at most limit votes if limit is positive, and
+// returns them ranked with RankDivergences.
+func FindDivergences(
+	votes source.VoteSource,
+	query sunlight.VoteQuery,
+	options Options,
+	limit int,
+) ([]Divergence, error) {
+	divergences := []Divergence{}
+	err := eachTally(
+		votes,
+		query,
+		options,
+		limit,
+		func(vote sunlight.Vote, tally Tally) {
+			divergences = append(divergences, NewDivergence(vote, tally))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	RankDivergences(divergences)
+	return divergences, nil
+}
+
+type byDivergence []Divergence
+
+func (d byDivergence) Len() int      { return len(d) }
+func (d byDivergence) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byDivergence) Less(i, j int) bool {
+	if d[i].CounterMajoritarian != d[j].CounterMajoritarian {
+		return d[i].CounterMajoritarian
+	}
+	return math.Abs(d[i].Gap) > math.Abs(d[j].Gap)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"sort"
+	"testing"
+	"time"
+)
+
+// counterMajoritarianSource returns a source holding two senate votes:
+// s1-2014, on which the senators of the 26 least populous states voted
+// yea and everyone else nay, and s2-2014, an hour later, on which
+// everyone voted yea.  The senators of the 26 least populous states
+// are enough to pass a question on their own, while representing well
+// under half the population.
+func counterMajoritarianSource(t *testing.T) *source.Memory {
+	states := census.RepresentedStates()
+	sort.Sort(byTestPopulation{states, t})
+
+	voters := map[string]sunlight.Voter{}
+	for i, state := range states {
+		position := sunlight.No
+		if i < 26 {
+			position = sunlight.Yes
+		}
+		for seat := 0; seat < seatsPerState; seat++ {
+			v := senator(state, seat, position)
+			voters[v.Info.BioguideID] = v
+		}
+	}
+
+	memory := source.NewMemory()
+	memory.AddVote(sunlight.Vote{
+		RollID:   "s1-2014",
+		Chamber:  sunlight.Senate,
+		VotedAt:  testVotedAt,
+		Required: "1/2",
+		Voters:   voters,
+	})
+	memory.AddVote(sunlight.Vote{
+		RollID:   "s2-2014",
+		Chamber:  sunlight.Senate,
+		VotedAt:  testVotedAt.Add(time.Hour),
+		Required: "1/2",
+		Voters:   fullSenate(100, 0),
+	})
+	return memory
+}
+
+func TestFindDivergences(t *testing.T) {
+	divergences, err := FindDivergences(
+		counterMajoritarianSource(t),
+		sunlight.VoteQuery{},
+		Options{},
+		0,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(divergences) != 2 {
+		t.Fatalf("Found %d divergences, want 2", len(divergences))
+	}
+
+	d := divergences[0]
+	if d.RollID != "s1-2014" || !d.CounterMajoritarian {
+		t.Errorf("Ranked %s first, counter-majoritarian %v", d.RollID, d.CounterMajoritarian)
+	}
+	if !d.SenatePassed || d.PopularPassed {
+		t.Errorf("Senate passed %v, popular passed %v", d.SenatePassed, d.PopularPassed)
+	}
+	if d.SenateYea != 52 || d.PopularYea >= 50 {
+		t.Errorf("Got %v%% of senators and %v%% of people", d.SenateYea, d.PopularYea)
+	}
+	if divergences[1].CounterMajoritarian {
+		t.Errorf("Unanimous vote flagged as counter-majoritarian")
+	}
+}
+
+// byTestPopulation sorts states from least to most populous at
+// testVotedAt.
+type byTestPopulation struct {
+	states []string
+	t      *testing.T
+}
+
+func (s byTestPopulation) Len() int { return len(s.states) }
+func (s byTestPopulation) Swap(i, j int) {
+	s.states[i], s.states[j] = s.states[j], s.states[i]
+}
+func (s byTestPopulation) Less(i, j int) bool {
+	return statePopulation(s.t, s.states[i]) < statePopulation(s.t, s.states[j])
+}
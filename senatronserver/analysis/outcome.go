@@ -0,0 +1,102 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+)
+
+// SenateYeas returns the number of senators who voted for the
+// question.
+func (t Tally) SenateYeas() int {
+	return t.Senate[sunlight.Yes]
+}
+
+// SenateNays returns the number of senators who voted against the
+// question.
+func (t Tally) SenateNays() int {
+	return t.Senate[sunlight.No]
+}
+
+// PopularYeas returns the population represented by senators who
+// voted for the question.
+func (t Tally) PopularYeas() float64 {
+	return t.Popular[sunlight.Yes]
+}
+
+// PopularNays returns the population represented by senators who
+// voted against the question.
+func (t Tally) PopularNays() float64 {
+	return t.Popular[sunlight.No]
+}
+
+// SenatePassed reports whether enough senators voted for the question
+// to meet its threshold.  Ties broken by the Vice President aren't
+// accounted for.
+func (t Tally) SenatePassed() bool {
+	return t.Threshold.Passes(
+		float64(t.SenateYeas()),
+		float64(t.SenateNays()),
+		float64(t.SenateTotal),
+	)
+}
+
+// PopularPassed reports whether the senators who voted for the
+// question represented enough people to meet its threshold.  For
+// thresholds of the whole membership, that's measured against the
+// whole represented population, including that of absent senators
+// and vacant seats.
+func (t Tally) PopularPassed() bool {
+	return t.Threshold.Passes(
+		t.PopularYeas(),
+		t.PopularNays(),
+		t.PopularTotal,
+	)
+}
+
+// Summary describes the chamber and popular outcomes in words.  A
+// tally without a scheme is assumed to be weighted by population.
+func (t Tally) Summary() string {
+	senate, popular := "failed", "fail"
+	if t.SenatePassed() {
+		senate = "passed"
+	}
+	if t.PopularPassed() {
+		popular = "pass"
+	}
+
+	if t.SenatePassed() == t.PopularPassed() {
+		popular = "also " + popular
+	}
+
+	scheme, ok := schemeLabels[t.Scheme]
+	if !ok {
+		scheme = schemeLabels[Population]
+	}
+
+	return fmt.Sprintf(
+		"%s in %s, would %s under %s weighting",
+		senate,
+		t.Chamber,
+		popular,
+		scheme,
+	)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"sort"
+)
+
+// PartyTally breaks down the votes of a single party's senators, and
+// the population they represented, by position.
+type PartyTally struct {
+	Senate  map[sunlight.Position]int     `json:"senate"`
+	Popular map[sunlight.Position]float64 `json:"popular"`
+
+	SenateTotal  int     `json:"senate_total"`
+	PopularTotal float64 `json:"popular_total"`
+}
+
+func newPartyTally() *PartyTally {
+	return &PartyTally{
+		Senate:  map[sunlight.Position]int{},
+		Popular: map[sunlight.Position]float64{},
+	}
+}
+
+// add counts a senator's vote along with the population they carried.
+func (p *PartyTally) add(position sunlight.Position, population float64) {
+	p.Senate[position]++
+	p.SenateTotal++
+	p.Popular[position] += population
+	p.PopularTotal += population
+}
+
+// PartyNames returns the parties ("D", "R", "I" and so on) with at
+// least one senator in the tally, in alphabetical order.
+func (t Tally) PartyNames() []string {
+	out := make([]string, 0, len(t.Parties))
+	for k := range t.Parties {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PartyPositions returns every position that at least one of the
+// given party's senators took, in the order of sunlight.Positions.
+func (t Tally) PartyPositions(party string) []sunlight.Position {
+	p, ok := t.Parties[party]
+	if !ok {
+		return []sunlight.Position{}
+	}
+
+	out := make([]sunlight.Position, 0, len(p.Senate))
+	for k := range p.Senate {
+		out = append(out, k)
+	}
+	sort.Sort(byPosition(out))
+	return out
+}
+
+// PartySenatePercent returns the percentage of the whole senate made
+// up of the given party's senators who took the given position.
+func (t Tally) PartySenatePercent(
+	party string,
+	position sunlight.Position,
+) float64 {
+	p, ok := t.Parties[party]
+	if !ok || t.SenateTotal == 0 {
+		return 0
+	}
+	return float64(p.Senate[position]) / float64(t.SenateTotal) * 100
+}
+
+// PartyPopularPercent returns the percentage of the whole represented
+// population whose senators were of the given party and took the given
+// position, as in "Republicans representing 30% of Americans voted
+// yea".
+func (t Tally) PartyPopularPercent(
+	party string,
+	position sunlight.Position,
+) float64 {
+	p, ok := t.Parties[party]
+	if !ok || t.PopularTotal == 0 {
+		return 0
+	}
+	return p.Popular[position] / t.PopularTotal * 100
+}
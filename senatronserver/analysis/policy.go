@@ -0,0 +1,202 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"time"
+)
+
+// Policy decides how a state's population (or whatever weight its
+// Scheme gives it) is attributed to its members when they don't all
+// cast a vote.
+type Policy string
+
+const (
+	// Halves gives each senator half of their state's population,
+	// no matter what.  Members who didn't vote carry their share into
+	// their own position (sunlight.NotVoting and so on), and vacant
+	// seats carry theirs into Vacant.  It's the only policy that
+	// applies to the House, where every member carries their own
+	// district's share.
+	Halves Policy = "halves"
+
+	// LoneVoter gives a state's full population to its senator when
+	// they're the only one from their state to cast a vote, because
+	// the other seat is vacant or its senator didn't vote.  Otherwise
+	// it works like Halves.
+	LoneVoter Policy = "lone-voter"
+)
+
+// Policies lists every policy.
+var Policies = []Policy{Halves, LoneVoter}
+
+// ParsePolicy returns the policy with the given name, or an error if
+// there's no such policy.  An empty name selects Halves.
+func ParsePolicy(name string) (Policy, error) {
+	if name == "" {
+		return Halves, nil
+	}
+	for _, policy := range Policies {
+		if string(policy) == name {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("analysis: Unknown policy %q", name)
+}
+
+// Vacant is the position that the population behind vacant seats is
+// attributed to.
+const Vacant sunlight.Position = "vacant"
+
+// seatsPerState is the number of senators each state gets.
+const seatsPerState = 2
+
+// attribute splits a state's population between the members of its
+// delegation according to the policy, returning each member's share
+// (in the same order as the members were given) along with the share
+// that goes to vacant seats.
+func (p Policy) attribute(
+	population float64,
+	seats int,
+	senators []sunlight.Voter,
+) (shares []float64, vacant float64) {
+	shares = make([]float64, len(senators))
+	if seats == 0 {
+		return
+	}
+	share := population / float64(seats)
+
+	lone := -1
+	if p == LoneVoter {
+		for i, senator := range senators {
+			if !senator.Vote.Cast() {
+				continue
+			}
+			if lone != -1 {
+				lone = -1
+				break
+			}
+			lone = i
+		}
+	}
+
+	if lone != -1 {
+		shares[lone] = population
+		return
+	}
+
+	for i := range senators {
+		shares[i] = share
+	}
+	if len(senators) < seats {
+		vacant = share * float64(seats-len(senators))
+	}
+	return
+}
+
+// split divides a state's weight between the members of its
+// delegation in the given chamber, returning each member's share (in
+// the same order as the members were given) along with the share that
+// goes to vacant seats and the number of them.  Senators split it
+// according to the policy, while representatives split it as by
+// districtShares.
+func split(
+	chamber string,
+	policy Policy,
+	state string,
+	weight float64,
+	seats int,
+	members []sunlight.Voter,
+	at time.Time,
+) (shares []float64, vacant float64, vacancies int, err error) {
+	if chamber == sunlight.House {
+		return districtShares(state, weight, members, at)
+	}
+
+	shares, vacant = policy.attribute(weight, seats, members)
+	if len(members) < seats {
+		vacancies = seats - len(members)
+	}
+	return
+}
+
+// districtShares divides a state's weight between its representatives
+// in proportion to the populations of the districts they represent at
+// the given time (as by census.GetDistrictAt), returning each member's
+// share along with the share that goes to districts without a member
+// and the number of them.  It returns census.ErrDistrictNotFound if
+// any member's district isn't one of the state's.
+func districtShares(
+	state string,
+	weight float64,
+	members []sunlight.Voter,
+	at time.Time,
+) (shares []float64, vacant float64, vacancies int, err error) {
+	districts, err := census.Districts(state, at)
+	if err != nil {
+		return
+	}
+
+	populations := make(map[int]float64, len(districts))
+	total := 0.0
+	for _, district := range districts {
+		population, err := census.GetDistrictAt(state, district, at)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		populations[district] = float64(population)
+		total += float64(population)
+	}
+
+	shares = make([]float64, len(members))
+	represented := map[int]bool{}
+	for i, member := range members {
+		population, ok := populations[member.Info.District]
+		if !ok {
+			return nil, 0, 0, census.ErrDistrictNotFound
+		}
+		shares[i] = weight * population / total
+		represented[member.Info.District] = true
+	}
+
+	for _, district := range districts {
+		if !represented[district] {
+			vacant += weight * populations[district] / total
+			vacancies++
+		}
+	}
+	return
+}
+
+// seatsAt returns the number of seats the given state had in the given
+// chamber at the time of a vote, or an error if it had none.
+func seatsAt(chamber, state string, vote sunlight.Vote) (int, error) {
+	if chamber == sunlight.House {
+		return census.SeatsAt(state, votedAt(vote))
+	}
+
+	if !census.IsRepresented(state) {
+		return 0, census.ErrNoSeats
+	}
+	return seatsPerState, nil
+}
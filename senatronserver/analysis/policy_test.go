@@ -0,0 +1,76 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"reflect"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		want Policy
+		ok   bool
+	}{
+		{"", Halves, true},
+		{"halves", Halves, true},
+		{"lone-voter", LoneVoter, true},
+		{"bogus", "", false},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePolicy(test.name)
+		if got != test.want || (err == nil) != test.ok {
+			t.Errorf("%q: Got %q, %v", test.name, got, err)
+		}
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	yea := sunlight.Voter{Vote: sunlight.Yes}
+	absent := sunlight.Voter{Vote: sunlight.NotVoting}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		seats    int
+		senators []sunlight.Voter
+		shares   []float64
+		vacant   float64
+	}{
+		{"both voting", Halves, 2, []sunlight.Voter{yea, yea}, []float64{50, 50}, 0},
+		{"vacancy", Halves, 2, []sunlight.Voter{yea}, []float64{50}, 50},
+		{"absence", Halves, 2, []sunlight.Voter{yea, absent}, []float64{50, 50}, 0},
+		{"lone beside a vacancy", LoneVoter, 2, []sunlight.Voter{yea}, []float64{100}, 0},
+		{"lone beside an absence", LoneVoter, 2, []sunlight.Voter{absent, yea}, []float64{0, 100}, 0},
+		{"nobody voting", LoneVoter, 2, []sunlight.Voter{absent, absent}, []float64{50, 50}, 0},
+		{"both voting", LoneVoter, 2, []sunlight.Voter{yea, yea}, []float64{50, 50}, 0},
+		{"no seats", Halves, 0, []sunlight.Voter{yea}, []float64{0}, 0},
+		{"house seat", Halves, 4, []sunlight.Voter{yea, yea, yea}, []float64{25, 25, 25}, 25},
+	}
+
+	for _, test := range tests {
+		shares, vacant := test.policy.attribute(100, test.seats, test.senators)
+		if !reflect.DeepEqual(shares, test.shares) || vacant != test.vacant {
+			t.Errorf("%s under %s: Got %v and %v vacant", test.name, test.policy, shares, vacant)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+)
+
+// Profile measures how representative a single senator's votes have
+// been.
+type Profile struct {
+	Legislator sunlight.Legislator `json:"legislator"`
+
+	// Votes counts the votes examined that the senator was on record
+	// for, whether or not they voted either way.
+	Votes int `json:"votes"`
+
+	// Decided counts the votes where the senator voted for or against
+	// the question and the population-weighted vote wasn't tied, and
+	// WithMajority those where they voted the same way as the
+	// population-weighted majority.
+	Decided      int `json:"decided"`
+	WithMajority int `json:"with_majority"`
+
+	// AveragePopulation is the mean population the senator's vote
+	// carried across all the votes examined.
+	AveragePopulation float64 `json:"average_population"`
+}
+
+// MajorityPercent returns the percentage of decided votes where the
+// senator voted with the population-weighted majority, or 0 if there
+// weren't any.
+func (p Profile) MajorityPercent() float64 {
+	if p.Decided == 0 {
+		return 0
+	}
+	return float64(p.WithMajority) / float64(p.Decided) * 100
+}
+
+// NewProfile compares a senator's votes with the population-weighted
+// majority on every vote in the source that they took part in and
+// that matches the query (whose pagination is ignored), examining at
+// most limit votes if limit is positive.
+func NewProfile(
+	votes source.VoteSource,
+	legislator sunlight.Legislator,
+	query sunlight.VoteQuery,
+	options Options,
+	limit int,
+) (Profile, error) {
+	profile := Profile{Legislator: legislator}
+	query.Voter = legislator.BioguideID
+
+	population := float64(0)
+	err := eachTally(
+		votes,
+		query,
+		options,
+		limit,
+		func(vote sunlight.Vote, tally Tally) {
+			senator, ok := tally.senator(legislator.BioguideID)
+			if !ok {
+				return
+			}
+			profile.Votes++
+			population += senator.Population
+
+			yeas, nays := tally.PopularYeas(), tally.PopularNays()
+			if yeas == nays {
+				return
+			}
+			switch senator.Vote {
+			case sunlight.Yes:
+				profile.Decided++
+				if yeas > nays {
+					profile.WithMajority++
+				}
+			case sunlight.No:
+				profile.Decided++
+				if nays > yeas {
+					profile.WithMajority++
+				}
+			}
+		},
+	)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if profile.Votes > 0 {
+		profile.AveragePopulation = population / float64(profile.Votes)
+	}
+	return profile, nil
+}
+
+// senator returns the tally for the senator with the given bioguide
+// ID, if they voted.
+func (t Tally) senator(bioguideID string) (SenatorTally, bool) {
+	for _, s := range t.Senators {
+		if s.BioguideID == bioguideID {
+			return s, true
+		}
+	}
+	return SenatorTally{}, false
+}
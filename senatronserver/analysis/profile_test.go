@@ -0,0 +1,59 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"testing"
+)
+
+func TestNewProfile(t *testing.T) {
+	votes := counterMajoritarianSource(t)
+
+	tests := []struct {
+		bioguideID   string
+		withMajority int
+	}{
+		// A big-state senator voted nay on the first vote and yea on
+		// the second, with the popular majority both times, while a
+		// small-state senator was only with it on the second.
+		{"CA0", 2},
+		{"WY0", 1},
+	}
+
+	for _, test := range tests {
+		profile, err := NewProfile(
+			votes,
+			sunlight.Legislator{BioguideID: test.bioguideID},
+			sunlight.VoteQuery{},
+			Options{},
+			0,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Votes != 2 || profile.Decided != 2 ||
+			profile.WithMajority != test.withMajority {
+			t.Errorf("%s: Got profile %+v", test.bioguideID, profile)
+		}
+		if profile.MajorityPercent() != float64(test.withMajority)*50 {
+			t.Errorf("%s: Got %v%%", test.bioguideID, profile.MajorityPercent())
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"math"
+	"time"
+)
+
+// Scheme decides how much weight each state carries in the popular
+// tally, before it's split between the state's members by a Policy.
+type Scheme string
+
+const (
+	// Population weights each state by its population under the
+	// tally's basis.
+	Population Scheme = "population"
+
+	// Apportionment weights each state by the number of House seats
+	// it was apportioned at the time of the vote.
+	Apportionment Scheme = "apportionment"
+
+	// ElectoralVotes weights each state by its electoral votes: its
+	// House seats plus two.  DC's three electoral votes count towards
+	// the unrepresented weight.
+	ElectoralVotes Scheme = "electoral-votes"
+
+	// Equal gives every state the same weight, which is how the
+	// senate itself works.
+	Equal Scheme = "equal"
+
+	// SquareRoot weights each state by the square root of its
+	// population, after Penrose, which gives every voter in the
+	// country the same chance of swinging the outcome through their
+	// state's delegation.
+	SquareRoot Scheme = "square-root"
+)
+
+// Schemes lists every scheme.
+var Schemes = []Scheme{
+	Population,
+	Apportionment,
+	ElectoralVotes,
+	Equal,
+	SquareRoot,
+}
+
+// schemeLabels describes each scheme in words.
+var schemeLabels = map[Scheme]string{
+	Population:     "population",
+	Apportionment:  "apportionment",
+	ElectoralVotes: "electoral vote",
+	Equal:          "equal",
+	SquareRoot:     "square root",
+}
+
+// dcElectoralVotes is the number of electoral votes the 23rd Amendment
+// gives DC.
+const dcElectoralVotes = 3
+
+// ParseScheme returns the scheme with the given name, or an error if
+// there's no such scheme.  An empty name selects Population.
+func ParseScheme(name string) (Scheme, error) {
+	if name == "" {
+		return Population, nil
+	}
+	for _, scheme := range Schemes {
+		if string(scheme) == name {
+			return scheme, nil
+		}
+	}
+	return "", fmt.Errorf("analysis: Unknown scheme %q", name)
+}
+
+// weight returns the weight the scheme gives a jurisdiction with the
+// given population at the given time.  Jurisdictions without senators
+// get no weight except under Population, SquareRoot and (for DC)
+// ElectoralVotes.
+func (s Scheme) weight(
+	state string,
+	population int,
+	at time.Time,
+) (float64, error) {
+	switch s {
+	case Apportionment, ElectoralVotes:
+		seats, err := census.SeatsAt(state, at)
+		if err == census.ErrNoSeats {
+			if s == ElectoralVotes && state == "DC" {
+				return dcElectoralVotes, nil
+			}
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+		if s == ElectoralVotes {
+			return float64(seats + seatsPerState), nil
+		}
+		return float64(seats), nil
+
+	case Equal:
+		if !census.IsRepresented(state) {
+			return 0, nil
+		}
+		return 1, nil
+
+	case SquareRoot:
+		return math.Sqrt(float64(population)), nil
+	}
+	return float64(population), nil
+}
+
+// SchemeOutcome is the popular vote on a roll call under a single
+// weighting scheme.
+type SchemeOutcome struct {
+	Scheme Scheme `json:"scheme"`
+
+	Yeas  float64 `json:"yeas"`
+	Nays  float64 `json:"nays"`
+	Total float64 `json:"total"`
+
+	// YeaPercent is the percentage of the weight cast either way that
+	// was cast for the question.
+	YeaPercent float64 `json:"yea_percent"`
+
+	Passed bool `json:"passed"`
+
+	// Flipped reports whether the question would have had a
+	// different outcome under the scheme than it did in the chamber.
+	Flipped bool `json:"flipped"`
+
+	// Summary describes both outcomes in words, as from
+	// Tally.Summary.
+	Summary string `json:"summary"`
+}
+
+// CompareSchemes tallies a vote under every scheme in turn, with the
+// rest of the options left as given, returning the outcomes in the
+// order of Schemes.
+func CompareSchemes(
+	vote sunlight.Vote,
+	options Options,
+) ([]SchemeOutcome, error) {
+	out := make([]SchemeOutcome, 0, len(Schemes))
+	for _, scheme := range Schemes {
+		options.Scheme = scheme
+		tally, err := NewTally(vote, options)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, SchemeOutcome{
+			Scheme:     scheme,
+			Yeas:       tally.PopularYeas(),
+			Nays:       tally.PopularNays(),
+			Total:      tally.PopularTotal,
+			YeaPercent: percent(tally.PopularYeas(), tally.PopularNays()),
+			Passed:     tally.PopularPassed(),
+			Flipped:    tally.PopularPassed() != tally.SenatePassed(),
+			Summary:    tally.Summary(),
+		})
+	}
+	return out, nil
+}
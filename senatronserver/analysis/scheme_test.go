@@ -0,0 +1,122 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"math"
+	"testing"
+)
+
+func TestParseScheme(t *testing.T) {
+	tests := []struct {
+		name string
+		want Scheme
+		ok   bool
+	}{
+		{"", Population, true},
+		{"population", Population, true},
+		{"electoral-votes", ElectoralVotes, true},
+		{"square-root", SquareRoot, true},
+		{"bogus", "", false},
+	}
+
+	for _, test := range tests {
+		got, err := ParseScheme(test.name)
+		if got != test.want || (err == nil) != test.ok {
+			t.Errorf("%q: Got %q, %v", test.name, got, err)
+		}
+	}
+}
+
+func TestCompareSchemes(t *testing.T) {
+	// The first 26 states alphabetically vote yea, the rest nay.
+	vote := sunlight.Vote{
+		RollID:   "s1-2014",
+		Chamber:  sunlight.Senate,
+		VotedAt:  testVotedAt,
+		Required: "1/2",
+		Voters:   fullSenate(52, 48),
+	}
+
+	population, squareRoot := 0.0, 0.0
+	for _, state := range census.RepresentedStates() {
+		population += statePopulation(t, state)
+		squareRoot += math.Sqrt(statePopulation(t, state))
+	}
+
+	totals := map[Scheme]float64{
+		Population:     population,
+		Apportionment:  435,
+		ElectoralVotes: 535,
+		Equal:          50,
+		SquareRoot:     squareRoot,
+	}
+	unrepresented := map[Scheme]bool{
+		Population:     true,
+		Apportionment:  false,
+		ElectoralVotes: true,
+		Equal:          false,
+		SquareRoot:     true,
+	}
+
+	outcomes, err := CompareSchemes(vote, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != len(Schemes) {
+		t.Fatalf("Got %d outcomes, want %d", len(outcomes), len(Schemes))
+	}
+
+	for i, outcome := range outcomes {
+		if outcome.Scheme != Schemes[i] {
+			t.Errorf("Outcome %d is for %s, want %s", i, outcome.Scheme, Schemes[i])
+		}
+		if math.Abs(outcome.Total-totals[outcome.Scheme]) > 1e-6 {
+			t.Errorf("%s: Got total %v, want %v", outcome.Scheme, outcome.Total, totals[outcome.Scheme])
+		}
+		// The senate passed it, so only failing flips it.
+		if outcome.Flipped == outcome.Passed {
+			t.Errorf("%s: Flipped is %v with passed %v", outcome.Scheme, outcome.Flipped, outcome.Passed)
+		}
+
+		tally, err := NewTally(vote, Options{Scheme: outcome.Scheme})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (tally.Unrepresented > 0) != unrepresented[outcome.Scheme] {
+			t.Errorf("%s: Got %v unrepresented", outcome.Scheme, tally.Unrepresented)
+		}
+	}
+
+	// Equal weighting is how the senate itself works.
+	equal := outcomes[3]
+	if equal.Yeas != 26 || equal.Nays != 24 || !equal.Passed {
+		t.Errorf("Equal: Got %+v", equal)
+	}
+
+	tally, err := NewTally(vote, Options{Scheme: ElectoralVotes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tally.Unrepresented != dcElectoralVotes {
+		t.Errorf("Got %v unrepresented electoral votes, want %v", tally.Unrepresented, dcElectoralVotes)
+	}
+}
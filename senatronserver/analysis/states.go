@@ -0,0 +1,151 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"errors"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"math"
+	"sort"
+)
+
+// ErrUnknownSort is returned by SortStates when asked to sort by a key
+// it doesn't know.
+var ErrUnknownSort = errors.New("analysis: Unknown sort key")
+
+// StateSortKeys lists the keys SortStates accepts.  States are sorted
+// alphabetically by "state", and from largest to smallest by
+// "population", "yea" and "nay".
+var StateSortKeys = []string{"state", "population", "yea", "nay"}
+
+// StateTally breaks down the vote of a single state's delegation.
+type StateTally struct {
+	State string `json:"state"`
+
+	// Population is the state's population under the tally's basis,
+	// or 0 if census has no figures for it.
+	Population float64 `json:"population"`
+
+	// Weight is what the tally's scheme made of the state's
+	// population, and what's split between its members in Popular.
+	// It's the same as Population under the Population scheme.
+	Weight float64 `json:"weight"`
+
+	// Seats is the number of seats the state had in the chamber, or 0
+	// if it's unknown.
+	Seats int `json:"seats"`
+
+	// Senators lists the delegation's votes, sorted by bioguide ID.
+	Senators []SenatorTally `json:"senators"`
+
+	Vacancies int `json:"vacancies"`
+
+	// Popular maps each position (including Vacant) to the share of
+	// the state's weight that went to it.
+	Popular map[sunlight.Position]float64 `json:"popular"`
+}
+
+// PopularYeas returns the share of the state's population whose
+// senators voted for the question.
+func (s StateTally) PopularYeas() float64 {
+	return s.Popular[sunlight.Yes]
+}
+
+// PopularNays returns the share of the state's population whose
+// senators voted against the question.
+func (s StateTally) PopularNays() float64 {
+	return s.Popular[sunlight.No]
+}
+
+// Malapportionment returns how far the chamber's seats were from being
+// shared out in proportion to population, as a percentage, using the
+// Loosemore-Hanby index: half the sum, over every state with seats, of
+// the difference between its share of the seats and its share of the
+// population.  It's 0 for perfect proportionality, and is the share of
+// seats that would have to move between states to get there.
+func (t Tally) Malapportionment() float64 {
+	seats, population := 0, float64(0)
+	for _, s := range t.States {
+		if s.Seats > 0 {
+			seats += s.Seats
+			population += s.Population
+		}
+	}
+	if seats == 0 || population == 0 {
+		return 0
+	}
+
+	index := float64(0)
+	for _, s := range t.States {
+		if s.Seats > 0 {
+			index += math.Abs(
+				float64(s.Seats)/float64(seats) - s.Population/population,
+			)
+		}
+	}
+	return index / 2 * 100
+}
+
+// SortStates sorts state tallies in place by one of the keys in
+// StateSortKeys, returning ErrUnknownSort for any other.  An empty key
+// sorts by state.
+func SortStates(states []StateTally, key string) error {
+	var less func(a, b StateTally) bool
+	switch key {
+	case "", "state":
+		less = func(a, b StateTally) bool { return false }
+	case "population":
+		less = func(a, b StateTally) bool {
+			return a.Population > b.Population
+		}
+	case "yea":
+		less = func(a, b StateTally) bool {
+			return a.PopularYeas() > b.PopularYeas()
+		}
+	case "nay":
+		less = func(a, b StateTally) bool {
+			return a.PopularNays() > b.PopularNays()
+		}
+	default:
+		return ErrUnknownSort
+	}
+
+	sort.Sort(stateSorter{states: states, less: less})
+	return nil
+}
+
+// stateSorter sorts state tallies by a comparison function, falling
+// back to alphabetical order for ties.
+type stateSorter struct {
+	states []StateTally
+	less   func(a, b StateTally) bool
+}
+
+func (s stateSorter) Len() int      { return len(s.states) }
+func (s stateSorter) Swap(i, j int) { s.states[i], s.states[j] = s.states[j], s.states[i] }
+func (s stateSorter) Less(i, j int) bool {
+	a, b := s.states[i], s.states[j]
+	if s.less(a, b) {
+		return true
+	} else if s.less(b, a) {
+		return false
+	}
+	return a.State < b.State
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"sort"
+	"time"
+)
+
+// Options controls how a Tally weights the popular vote.  The zero
+// value weights by total population.
+type Options struct {
+	Basis  census.Basis
+	Policy Policy
+	Scheme Scheme
+}
+
+// Tally compares the senate vote on a roll call with the popular vote
+// it represents, with each state's population split between its
+// senators according to a Policy.  All the maps are keyed by vote
+// position, plus Vacant in the popular tallies.
+//
+// House votes are tallied the same way, so for them the "senate"
+// fields and Senators describe representatives instead, and each
+// state's population is split between its districts in proportion to
+// their populations.
+type Tally struct {
+	RollID   string `json:"roll_id"`
+	Chamber  string `json:"chamber"`
+	Question string `json:"question"`
+
+	// Basis is the set of population figures the popular vote was
+	// weighted by.
+	Basis census.Basis `json:"basis"`
+
+	// Scheme is how much weight each state carried.  Under anything
+	// but Population, the popular tallies (and Unrepresented) count
+	// that weight rather than people.
+	Scheme Scheme `json:"scheme"`
+
+	// Policy is how each state's population was split between its
+	// senators.
+	Policy Policy `json:"policy"`
+
+	// Required is the vote's threshold as reported by the source, and
+	// Threshold is how it's applied to both the senate and popular
+	// votes.
+	Required  string    `json:"required"`
+	Threshold Threshold `json:"threshold"`
+
+	Senate  map[sunlight.Position]int     `json:"senate"`
+	Popular map[sunlight.Position]float64 `json:"popular"`
+
+	SenateTotal  int     `json:"senate_total"`
+	PopularTotal float64 `json:"popular_total"`
+
+	// Vacancies counts the senate seats with nobody in them.  Votes
+	// without any voters on record (like senate.gov menu summaries)
+	// aren't counted as being entirely vacant.
+	Vacancies int `json:"vacancies"`
+
+	// Unrepresented is the population of jurisdictions without any
+	// senators, like DC and Puerto Rico.  It's not included in the
+	// popular tally.
+	Unrepresented float64 `json:"unrepresented"`
+
+	// Parties breaks the senate and popular tallies down by the
+	// party of each senator.
+	Parties map[string]*PartyTally `json:"parties"`
+
+	// States breaks the tally down by state, sorted by state code.
+	States []StateTally `json:"states"`
+
+	// Senators breaks the tally down by individual senator, sorted
+	// by state.
+	Senators []SenatorTally `json:"senators"`
+
+	// UnknownStates lists any state codes that census has no
+	// population or seats for (or, in the House, figures for one of
+	// the state's members' districts).  Senators from those states
+	// still count in the senate tally, but contribute nothing to the
+	// popular one.
+	UnknownStates []string `json:"unknown_states"`
+}
+
+// SenatorTally records a single senator's vote along with the share
+// of the popular vote it carried.  For representatives, District is
+// the district they represent (0 for at-large seats).
+type SenatorTally struct {
+	BioguideID string            `json:"bioguide_id"`
+	State      string            `json:"state"`
+	District   int               `json:"district,omitempty"`
+	Party      string            `json:"party"`
+	Vote       sunlight.Position `json:"vote"`
+	Population float64           `json:"population"`
+}
+
+// NewTally counts up the senate and popular votes for the given
+// vote, using census figures interpolated to the year of the vote (as
+// by census.GetBasisAt).  It returns census.ErrBasisUnavailable if no
+// figures have been loaded for the basis selected in the options.
+func NewTally(vote sunlight.Vote, options Options) (Tally, error) {
+	basis := options.Basis
+	if basis == "" {
+		basis = census.Total
+	}
+	if !census.Available(basis) {
+		return Tally{}, census.ErrBasisUnavailable
+	}
+
+	chamber := vote.Chamber
+	if chamber == "" {
+		chamber = sunlight.Senate
+	}
+
+	policy := options.Policy
+	if policy == "" || chamber == sunlight.House {
+		policy = Halves
+	}
+
+	scheme := options.Scheme
+	if scheme == "" {
+		scheme = Population
+	}
+
+	tally := Tally{
+		RollID:        vote.RollID,
+		Chamber:       chamber,
+		Question:      vote.Question,
+		Basis:         basis,
+		Scheme:        scheme,
+		Policy:        policy,
+		Required:      vote.Required,
+		Threshold:     ParseThreshold(chamber, vote.Required, vote.Question),
+		Senate:        map[sunlight.Position]int{},
+		Popular:       map[sunlight.Position]float64{},
+		Parties:       map[string]*PartyTally{},
+		States:        make([]StateTally, 0, len(census.RepresentedStates())),
+		Senators:      make([]SenatorTally, 0, len(vote.Voters)),
+		UnknownStates: []string{},
+	}
+
+	delegations := map[string][]sunlight.Voter{}
+	if len(vote.Voters) > 0 {
+		for _, state := range census.RepresentedStates() {
+			delegations[state] = nil
+		}
+	}
+	for _, v := range vote.Voters {
+		delegations[v.Info.State] = append(delegations[v.Info.State], v)
+	}
+
+	for state, senators := range delegations {
+		stateTally := StateTally{
+			State:    state,
+			Senators: make([]SenatorTally, 0, len(senators)),
+			Popular:  map[sunlight.Position]float64{},
+		}
+
+		// Members from anywhere without seats (or population
+		// figures) still count in the chamber's tally, but carry no
+		// population.
+		seats, err := seatsAt(chamber, state, vote)
+		population, popErr := populationAt(basis, state, vote)
+		weight, weightErr := scheme.weight(state, population, votedAt(vote))
+		var shares []float64
+		var vacant float64
+		if err == nil && popErr == nil && weightErr == nil {
+			shares, vacant, stateTally.Vacancies, err = split(
+				chamber,
+				policy,
+				state,
+				weight,
+				seats,
+				senators,
+				votedAt(vote),
+			)
+		}
+		if err != nil || popErr != nil || weightErr != nil {
+			tally.UnknownStates = append(tally.UnknownStates, state)
+			seats, population, weight = 0, 0, 0
+			shares, vacant = make([]float64, len(senators)), 0
+			stateTally.Vacancies = 0
+		}
+		tally.Vacancies += stateTally.Vacancies
+		stateTally.Seats = seats
+		stateTally.Population = float64(population)
+		stateTally.Weight = weight
+
+		for i, v := range senators {
+			tally.Senate[v.Vote]++
+			tally.SenateTotal++
+			tally.Popular[v.Vote] += shares[i]
+			tally.PopularTotal += shares[i]
+
+			party, ok := tally.Parties[v.Info.Party]
+			if !ok {
+				party = newPartyTally()
+				tally.Parties[v.Info.Party] = party
+			}
+			party.add(v.Vote, shares[i])
+
+			senator := SenatorTally{
+				BioguideID: v.Info.BioguideID,
+				State:      v.Info.State,
+				District:   v.Info.District,
+				Party:      v.Info.Party,
+				Vote:       v.Vote,
+				Population: shares[i],
+			}
+			tally.Senators = append(tally.Senators, senator)
+			stateTally.Senators = append(stateTally.Senators, senator)
+			stateTally.Popular[v.Vote] += shares[i]
+		}
+		if vacant > 0 {
+			tally.Popular[Vacant] += vacant
+			tally.PopularTotal += vacant
+			stateTally.Popular[Vacant] += vacant
+		}
+
+		sort.Sort(byState(stateTally.Senators))
+		tally.States = append(tally.States, stateTally)
+	}
+	sort.Strings(tally.UnknownStates)
+	sort.Sort(byState(tally.Senators))
+	SortStates(tally.States, "state")
+
+	for _, code := range census.UnrepresentedJurisdictions() {
+		// Not every basis has figures for territories, in which
+		// case there's nothing we can report for them.
+		population, err := populationAt(basis, code, vote)
+		if err != nil {
+			continue
+		}
+		weight, err := scheme.weight(code, population, votedAt(vote))
+		if err == nil {
+			tally.Unrepresented += weight
+		}
+	}
+
+	return tally, nil
+}
+
+// populationAt returns the population of a state at the time of the
+// given vote, falling back to the most recent figures if the vote's
+// date is missing.
+func populationAt(
+	basis census.Basis,
+	state string,
+	vote sunlight.Vote,
+) (int, error) {
+	return census.GetBasisAt(basis, state, votedAt(vote))
+}
+
+// votedAt returns the time of the given vote, or the current time if
+// it's missing.
+func votedAt(vote sunlight.Vote) time.Time {
+	if vote.VotedAt.IsZero() {
+		return time.Now()
+	}
+	return vote.VotedAt
+}
+
+// UnrepresentedPercent returns the percentage of the total population
+// (represented or not) that had no senators at all.
+func (t Tally) UnrepresentedPercent() float64 {
+	total := t.PopularTotal + t.Unrepresented
+	if total == 0 {
+		return 0
+	}
+	return t.Unrepresented / total * 100
+}
+
+// Positions returns every vote position that at least one senator
+// took, along with Vacant if any population went to vacant seats, in
+// the order of sunlight.Positions with Vacant last.
+func (t Tally) Positions() []sunlight.Position {
+	out := make([]sunlight.Position, 0, len(t.Senate)+1)
+	for k := range t.Senate {
+		out = append(out, k)
+	}
+	if _, ok := t.Popular[Vacant]; ok {
+		out = append(out, Vacant)
+	}
+	sort.Sort(byPosition(out))
+	return out
+}
+
+// SenatePercent returns the percentage of senators who took the
+// given position, or 0 if nobody voted at all.
+func (t Tally) SenatePercent(position sunlight.Position) float64 {
+	if t.SenateTotal == 0 {
+		return 0
+	}
+	return float64(t.Senate[position]) / float64(t.SenateTotal) * 100
+}
+
+// PopularPercent returns the percentage of the represented
+// population whose senators took the given position, or 0 if nobody
+// was represented at all.
+func (t Tally) PopularPercent(position sunlight.Position) float64 {
+	if t.PopularTotal == 0 {
+		return 0
+	}
+	return t.Popular[position] / t.PopularTotal * 100
+}
+
+type byPosition []sunlight.Position
+
+func (p byPosition) Len() int           { return len(p) }
+func (p byPosition) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byPosition) Less(i, j int) bool { return rank(p[i]) < rank(p[j]) }
+
+// rank orders positions as in sunlight.Positions, with Vacant and any
+// others after them.
+func rank(position sunlight.Position) int {
+	for i, p := range sunlight.Positions {
+		if p == position {
+			return i
+		}
+	}
+	return len(sunlight.Positions)
+}
+
+type byState []SenatorTally
+
+func (s byState) Len() int      { return len(s) }
+func (s byState) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byState) Less(i, j int) bool {
+	if s[i].State != s[j].State {
+		return s[i].State < s[j].State
+	}
+	return s[i].BioguideID < s[j].BioguideID
+}
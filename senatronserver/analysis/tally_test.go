@@ -0,0 +1,445 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// testVotedAt is when every test vote was taken.
+var testVotedAt = time.Date(2014, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+// senator returns a voter with a made-up bioguide ID for the given
+// seat of a state.
+func senator(state string, seat int, position sunlight.Position) sunlight.Voter {
+	return sunlight.Voter{
+		Vote: position,
+		Info: sunlight.VoterInfo{
+			BioguideID: fmt.Sprintf("%s%d", state, seat),
+			State:      state,
+			Party:      "I",
+		},
+	}
+}
+
+// fullSenate returns every seat in the senate, going through the
+// states alphabetically, with the first yeas senators voting yea, the
+// next nays voting nay and the rest not voting.
+func fullSenate(yeas, nays int) map[string]sunlight.Voter {
+	states := census.RepresentedStates()
+	sort.Strings(states)
+
+	voters := map[string]sunlight.Voter{}
+	n := 0
+	for _, state := range states {
+		for seat := 0; seat < seatsPerState; seat++ {
+			position := sunlight.NotVoting
+			if n < yeas {
+				position = sunlight.Yes
+			} else if n < yeas+nays {
+				position = sunlight.No
+			}
+			v := senator(state, seat, position)
+			voters[v.Info.BioguideID] = v
+			n++
+		}
+	}
+	return voters
+}
+
+// without removes the given seats from a set of voters, leaving them
+// vacant.
+func without(
+	voters map[string]sunlight.Voter,
+	bioguideIDs ...string,
+) map[string]sunlight.Voter {
+	for _, id := range bioguideIDs {
+		delete(voters, id)
+	}
+	return voters
+}
+
+// with adds or replaces voters in a set of voters.
+func with(
+	voters map[string]sunlight.Voter,
+	added ...sunlight.Voter,
+) map[string]sunlight.Voter {
+	for _, v := range added {
+		voters[v.Info.BioguideID] = v
+	}
+	return voters
+}
+
+// statePopulation returns a state's total population at testVotedAt.
+func statePopulation(t *testing.T, state string) float64 {
+	population, err := census.GetBasisAt(census.Total, state, testVotedAt)
+	if err != nil {
+		t.Fatalf("%s: %v", state, err)
+	}
+	return float64(population)
+}
+
+// tallyTests are senate votes, all taken on testVotedAt, along with
+// what their tallies should come to.  Populations are given as shares
+// of the state's population.
+var tallyTests = []struct {
+	name     string
+	required string
+	question string
+	voters   map[string]sunlight.Voter
+	policy   Policy
+
+	threshold     Threshold
+	senateTotal   int
+	vacancies     int
+	senatePassed  bool
+	unknownStates []string
+
+	// shares maps bioguide IDs to the share of their state's
+	// population they should carry, and popularShares maps positions
+	// to the share of California's population that should go to them.
+	shares        map[string]float64
+	popularShares map[sunlight.Position]float64
+}{
+	{
+		name:         "vacancy",
+		required:     "1/2",
+		voters:       without(fullSenate(100, 0), "CA1"),
+		threshold:    Majority,
+		senateTotal:  99,
+		vacancies:    1,
+		senatePassed: true,
+		shares:       map[string]float64{"CA0": 0.5},
+		popularShares: map[sunlight.Position]float64{
+			Vacant: 0.5,
+		},
+	},
+	{
+		name:     "absence under halves",
+		required: "1/2",
+		voters: with(
+			fullSenate(100, 0),
+			senator("CA", 1, sunlight.NotVoting),
+		),
+		policy:       Halves,
+		threshold:    Majority,
+		senateTotal:  100,
+		senatePassed: true,
+		shares:       map[string]float64{"CA0": 0.5, "CA1": 0.5},
+		popularShares: map[sunlight.Position]float64{
+			sunlight.NotVoting: 0.5,
+		},
+	},
+	{
+		name:     "absence under lone-voter",
+		required: "1/2",
+		voters: with(
+			fullSenate(100, 0),
+			senator("CA", 1, sunlight.NotVoting),
+		),
+		policy:       LoneVoter,
+		threshold:    Majority,
+		senateTotal:  100,
+		senatePassed: true,
+		shares:       map[string]float64{"CA0": 1, "CA1": 0},
+		popularShares: map[sunlight.Position]float64{
+			sunlight.NotVoting: 0,
+		},
+	},
+	{
+		name:         "vacancy under lone-voter",
+		required:     "1/2",
+		voters:       without(fullSenate(100, 0), "CA1"),
+		policy:       LoneVoter,
+		threshold:    Majority,
+		senateTotal:  99,
+		vacancies:    1,
+		senatePassed: true,
+		shares:       map[string]float64{"CA0": 1},
+		popularShares: map[sunlight.Position]float64{
+			Vacant: 0,
+		},
+	},
+	{
+		name:         "both absent under lone-voter",
+		required:     "1/2",
+		voters:       fullSenate(98, 0),
+		policy:       LoneVoter,
+		threshold:    Majority,
+		senateTotal:  100,
+		senatePassed: true,
+		shares:       map[string]float64{"WY0": 0.5, "WY1": 0.5},
+	},
+	{
+		name:         "cloture invoked",
+		required:     "3/5",
+		question:     "On the Cloture Motion S. 2280",
+		voters:       fullSenate(60, 40),
+		threshold:    ThreeFifths,
+		senateTotal:  100,
+		senatePassed: true,
+	},
+	{
+		name:         "cloture short of the membership",
+		required:     "3/5",
+		question:     "On the Cloture Motion S. 2280",
+		voters:       fullSenate(59, 38),
+		threshold:    ThreeFifths,
+		senateTotal:  100,
+		senatePassed: false,
+	},
+	{
+		name:         "cloture by question",
+		question:     "On the Cloture Motion S. 2280",
+		voters:       fullSenate(59, 38),
+		threshold:    ThreeFifths,
+		senateTotal:  100,
+		senatePassed: false,
+	},
+	{
+		name:         "tie",
+		required:     "1/2",
+		voters:       fullSenate(50, 50),
+		threshold:    Majority,
+		senateTotal:  100,
+		senatePassed: false,
+	},
+	{
+		name:         "bare majority",
+		required:     "1/2",
+		voters:       fullSenate(51, 49),
+		threshold:    Majority,
+		senateTotal:  100,
+		senatePassed: true,
+	},
+	{
+		name:         "veto override",
+		required:     "2/3",
+		voters:       fullSenate(66, 33),
+		threshold:    TwoThirds,
+		senateTotal:  100,
+		senatePassed: true,
+	},
+	{
+		name:     "unknown state",
+		required: "1/2",
+		voters: with(
+			fullSenate(100, 0),
+			senator("ZZ", 0, sunlight.No),
+		),
+		threshold:     Majority,
+		senateTotal:   101,
+		senatePassed:  true,
+		unknownStates: []string{"ZZ"},
+		shares:        map[string]float64{"ZZ0": 0},
+	},
+}
+
+func TestNewTally(t *testing.T) {
+	memory := source.NewMemory()
+	senatePassed := map[string]bool{}
+	for i, test := range tallyTests {
+		vote := sunlight.Vote{
+			RollID:   fmt.Sprintf("s%d-2014", i+1),
+			Chamber:  sunlight.Senate,
+			VotedAt:  testVotedAt,
+			Required: test.required,
+			Question: test.question,
+			Voters:   test.voters,
+		}
+		memory.AddVote(vote)
+		if test.policy == "" {
+			senatePassed[vote.RollID] = test.senatePassed
+		}
+
+		tally, err := NewTally(vote, Options{Policy: test.policy})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if tally.Threshold != test.threshold {
+			t.Errorf("%s: Got threshold %v, want %v", test.name, tally.Threshold, test.threshold)
+		}
+		if tally.SenateTotal != test.senateTotal {
+			t.Errorf("%s: Got %d senators, want %d", test.name, tally.SenateTotal, test.senateTotal)
+		}
+		if tally.Vacancies != test.vacancies {
+			t.Errorf("%s: Got %d vacancies, want %d", test.name, tally.Vacancies, test.vacancies)
+		}
+		if tally.SenatePassed() != test.senatePassed {
+			t.Errorf("%s: Senate passed is %v", test.name, tally.SenatePassed())
+		}
+
+		unknownStates := test.unknownStates
+		if unknownStates == nil {
+			unknownStates = []string{}
+		}
+		if !reflect.DeepEqual(tally.UnknownStates, unknownStates) {
+			t.Errorf("%s: Got unknown states %v, want %v", test.name, tally.UnknownStates, unknownStates)
+		}
+
+		for id, share := range test.shares {
+			s, ok := tally.senator(id)
+			if !ok {
+				t.Errorf("%s: %s missing from the tally", test.name, id)
+				continue
+			}
+			want := 0.0
+			if s.State != "ZZ" {
+				want = share * statePopulation(t, s.State)
+			}
+			if math.Abs(s.Population-want) > 1e-6 {
+				t.Errorf("%s: %s carried %v, want %v", test.name, id, s.Population, want)
+			}
+		}
+
+		california := statePopulation(t, "CA")
+		for position, share := range test.popularShares {
+			got := tally.Popular[position]
+			if math.Abs(got-share*california) > 1e-6 {
+				t.Errorf("%s: %s carried %v, want %v", test.name, position, got, share*california)
+			}
+		}
+
+		// Every represented person is accounted for, whoever (if
+		// anyone) they went to.
+		total := 0.0
+		for _, state := range census.RepresentedStates() {
+			total += statePopulation(t, state)
+		}
+		if math.Abs(tally.PopularTotal-total) > 1e-3 {
+			t.Errorf("%s: Popular total %v, want %v", test.name, tally.PopularTotal, total)
+		}
+	}
+
+	// Running the same votes through a source should come to the same
+	// outcomes, for those tallied with the default options.
+	divergences, err := FindDivergences(memory, sunlight.VoteQuery{}, Options{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(divergences) != len(tallyTests) {
+		t.Fatalf("Found %d divergences, want %d", len(divergences), len(tallyTests))
+	}
+	for _, d := range divergences {
+		want, ok := senatePassed[d.RollID]
+		if ok && d.SenatePassed != want {
+			t.Errorf("%s: Senate passed is %v", d.RollID, d.SenatePassed)
+		}
+	}
+}
+
+func TestNewTallyHouse(t *testing.T) {
+	voters := map[string]sunlight.Voter{}
+	for district := 1; district <= 3; district++ {
+		v := senator("CA", district, sunlight.Yes)
+		v.Info.District = district
+		voters[v.Info.BioguideID] = v
+	}
+	// Puerto Rico's resident commissioner votes in the committee of
+	// the whole, but the state has no seats.
+	voters["PR0"] = senator("PR", 0, sunlight.No)
+
+	tally, err := NewTally(
+		sunlight.Vote{
+			RollID:   "h1-2014",
+			Chamber:  sunlight.House,
+			VotedAt:  testVotedAt,
+			Required: "2/3 YEA-AND-NAY",
+			Question: "On Motion to Suspend the Rules and Pass",
+			Voters:   voters,
+		},
+		Options{Policy: LoneVoter},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seats, err := census.SeatsAt("CA", testVotedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tally.Policy != Halves {
+		t.Errorf("Got policy %s, want %s", tally.Policy, Halves)
+	}
+	if tally.Threshold != TwoThirds {
+		t.Errorf("Got threshold %v", tally.Threshold)
+	}
+	if tally.Vacancies != 435-3 {
+		t.Errorf("Got %d vacancies, want %d", tally.Vacancies, 435-3)
+	}
+	if !reflect.DeepEqual(tally.UnknownStates, []string{"PR"}) {
+		t.Errorf("Got unknown states %v", tally.UnknownStates)
+	}
+
+	// Each member carries their district's share of the state.
+	total := 0.0
+	districts := map[int]float64{}
+	for district := 1; district <= seats; district++ {
+		population, err := census.GetDistrictAt("CA", district, testVotedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		districts[district] = float64(population)
+		total += float64(population)
+	}
+	for _, s := range tally.Senators {
+		want := 0.0
+		if s.State == "CA" {
+			want = statePopulation(t, "CA") * districts[s.District] / total
+		}
+		if s.State != "PR" && s.District == 0 {
+			t.Errorf("%s: Lost their district", s.BioguideID)
+		}
+		if math.Abs(s.Population-want) > 1e-6 {
+			t.Errorf("%s: Carried %v, want %v", s.BioguideID, s.Population, want)
+		}
+	}
+
+	// A member from a district their state doesn't have leaves the
+	// whole state unknown.
+	bogus := senator("CA", 99, sunlight.Yes)
+	bogus.Info.District = 99
+	tally, err = NewTally(
+		sunlight.Vote{
+			RollID:  "h2-2014",
+			Chamber: sunlight.House,
+			VotedAt: testVotedAt,
+			Voters:  map[string]sunlight.Voter{"CA99": bogus},
+		},
+		Options{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tally.UnknownStates, []string{"CA"}) {
+		t.Errorf("Got unknown states %v", tally.UnknownStates)
+	}
+	if tally.Vacancies != 435-seats {
+		t.Errorf("Got %d vacancies, want %d", tally.Vacancies, 435-seats)
+	}
+}
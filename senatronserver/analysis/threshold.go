@@ -0,0 +1,127 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"strings"
+)
+
+// Threshold is the share of the vote a question needs to pass.
+type Threshold struct {
+	Numerator   int `json:"numerator"`
+	Denominator int `json:"denominator"`
+
+	// Strict thresholds need more than the given share to pass,
+	// rather than at least that share.  Simple majorities are
+	// strict, so that a tie fails.
+	Strict bool `json:"strict"`
+
+	// OfMembership is set when the share is of every sitting member
+	// (and so of the whole represented population), rather than just
+	// of those voting either way, as it is for cloture.
+	OfMembership bool `json:"of_membership"`
+}
+
+// The thresholds the senate uses.  The House uses Majority and
+// TwoThirds too, the latter to suspend the rules.
+var (
+	// Majority is needed for most questions.
+	Majority = Threshold{Numerator: 1, Denominator: 2, Strict: true}
+
+	// ThreeFifths of senators duly chosen and sworn are needed to
+	// invoke cloture, and to waive some budget rules.
+	ThreeFifths = Threshold{Numerator: 3, Denominator: 5, OfMembership: true}
+
+	// TwoThirds of those voting are needed to override a veto, ratify
+	// a treaty, propose a constitutional amendment or convict on
+	// impeachment.
+	TwoThirds = Threshold{Numerator: 2, Denominator: 3}
+)
+
+// ParseThreshold returns the threshold described by a vote's required
+// field, which starts with "1/2", "3/5" or "2/3" (the House follows
+// that with the kind of vote, as in "2/3 YEA-AND-NAY").  If that's
+// missing or unrecognised, the question is checked for cloture
+// motions, veto overrides and motions to suspend the rules, and
+// anything else is assumed to need a simple majority.  Three fifths
+// in the House is of those voting, unlike senate cloture.
+func ParseThreshold(chamber, required, question string) Threshold {
+	share := ""
+	if fields := strings.Fields(required); len(fields) > 0 {
+		share = fields[0]
+	}
+
+	switch share {
+	case "1/2":
+		return Majority
+	case "3/5":
+		if chamber == sunlight.House {
+			return Threshold{Numerator: 3, Denominator: 5}
+		}
+		return ThreeFifths
+	case "2/3":
+		return TwoThirds
+	}
+
+	question = strings.ToLower(question)
+	switch {
+	case strings.Contains(question, "cloture"):
+		return ThreeFifths
+	case strings.Contains(question, "overriding the veto"),
+		strings.Contains(question, "suspend the rules"):
+		return TwoThirds
+	}
+	return Majority
+}
+
+// Passes reports whether the given yeas are enough to pass, given the
+// nays and (for thresholds of the whole membership) the total.
+func (t Threshold) Passes(yeas, nays, total float64) bool {
+	base := yeas + nays
+	if t.OfMembership {
+		base = total
+	}
+	if base == 0 {
+		return false
+	}
+
+	// Compare cross-multiplied to avoid rounding trouble with
+	// thirds.
+	needed := base * float64(t.Numerator)
+	got := yeas * float64(t.Denominator)
+	if t.Strict {
+		return got > needed
+	}
+	return got >= needed
+}
+
+// String describes the threshold in words.
+func (t Threshold) String() string {
+	of := "of those voting"
+	if t.OfMembership {
+		of = "of all sitting members"
+	}
+	if t.Strict {
+		return fmt.Sprintf("more than %d/%d %s", t.Numerator, t.Denominator, of)
+	}
+	return fmt.Sprintf("%d/%d %s", t.Numerator, t.Denominator, of)
+}
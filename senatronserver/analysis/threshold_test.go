@@ -0,0 +1,125 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package analysis
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"testing"
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		chamber  string
+		required string
+		question string
+		want     Threshold
+	}{
+		{sunlight.Senate, "1/2", "On Passage of the Bill", Majority},
+		{sunlight.Senate, "3/5", "On the Cloture Motion", ThreeFifths},
+		{sunlight.Senate, "2/3", "On Overriding the Veto", TwoThirds},
+		{sunlight.Senate, "", "On the Cloture Motion", ThreeFifths},
+		{sunlight.Senate, "", "On Overriding the Veto", TwoThirds},
+		{sunlight.Senate, "", "On Passage of the Bill", Majority},
+		{sunlight.Senate, "QUORUM", "On the Motion", Majority},
+		{sunlight.House, "1/2", "On Passage", Majority},
+		{
+			sunlight.House,
+			"2/3 YEA-AND-NAY",
+			"On Motion to Suspend the Rules and Pass",
+			TwoThirds,
+		},
+		{
+			sunlight.House,
+			"",
+			"On Motion to Suspend the Rules and Pass, as Amended",
+			TwoThirds,
+		},
+		{
+			sunlight.House,
+			"3/5 RECORDED VOTE",
+			"On Agreeing to the Amendment",
+			Threshold{Numerator: 3, Denominator: 5},
+		},
+	}
+
+	for _, test := range tests {
+		got := ParseThreshold(test.chamber, test.required, test.question)
+		if got != test.want {
+			t.Errorf(
+				"%s %q %q: Got %v, want %v",
+				test.chamber,
+				test.required,
+				test.question,
+				got,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestThresholdPasses(t *testing.T) {
+	tests := []struct {
+		threshold         Threshold
+		yeas, nays, total float64
+		want              bool
+	}{
+		{Majority, 51, 49, 100, true},
+		{Majority, 50, 50, 100, false},
+		{Majority, 2, 1, 100, true},
+		{Majority, 0, 0, 100, false},
+		{TwoThirds, 66, 33, 100, true},
+		{TwoThirds, 66, 34, 100, false},
+		{TwoThirds, 2, 1, 3, true},
+		{ThreeFifths, 60, 40, 100, true},
+		{ThreeFifths, 59, 38, 100, false},
+		{ThreeFifths, 60, 0, 100, true},
+		{ThreeFifths, 0, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		got := test.threshold.Passes(test.yeas, test.nays, test.total)
+		if got != test.want {
+			t.Errorf(
+				"%v with %v-%v of %v: Got %v",
+				test.threshold,
+				test.yeas,
+				test.nays,
+				test.total,
+				got,
+			)
+		}
+	}
+}
+
+func TestThresholdString(t *testing.T) {
+	tests := []struct {
+		threshold Threshold
+		want      string
+	}{
+		{Majority, "more than 1/2 of those voting"},
+		{TwoThirds, "2/3 of those voting"},
+		{ThreeFifths, "3/5 of all sitting members"},
+	}
+
+	for _, test := range tests {
+		if got := test.threshold.String(); got != test.want {
+			t.Errorf("Got %q, want %q", got, test.want)
+		}
+	}
+}
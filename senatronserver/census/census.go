@@ -21,80 +21,176 @@ package census
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 )
 
-// Based on 2014 census estimates from
-// www.census.gov/popest/data/state/totals/2014/tables/NST-EST2014-01.csv
-var populations = map[string]int{
-	"AK": 736732,
-	"AL": 4849377,
-	"AR": 2966369,
-	"AZ": 6731484,
-	"CA": 38802500,
-	"CO": 5355866,
-	"CT": 3596677,
-	"DC": 658893,
-	"DE": 935614,
-	"FL": 19893297,
-	"GA": 10097343,
-	"HI": 1419561,
-	"IA": 3107126,
-	"ID": 1634464,
-	"IL": 12880580,
-	"IN": 6596855,
-	"KS": 2904021,
-	"KY": 4413457,
-	"LA": 4649676,
-	"MA": 6745408,
-	"MD": 5976407,
-	"ME": 1330089,
-	"MI": 9909877,
-	"MN": 5457173,
-	"MO": 6063589,
-	"MS": 2994079,
-	"MT": 1023579,
-	"NC": 9943964,
-	"ND": 739482,
-	"NE": 1881503,
-	"NH": 1326813,
-	"NJ": 8938175,
-	"NM": 2085572,
-	"NV": 2839099,
-	"NY": 19746227,
-	"OH": 11594163,
-	"OK": 3878051,
-	"OR": 3970239,
-	"PA": 12787209,
-	"PR": 3548397,
-	"RI": 1055173,
-	"SC": 4832482,
-	"SD": 853175,
-	"TN": 6549352,
-	"TX": 26956958,
-	"UT": 2942902,
-	"VA": 8326289,
-	"VT": 626562,
-	"WA": 7061530,
-	"WI": 5757564,
-	"WV": 1850326,
-	"WY": 584153,
+// Dataset is a set of population figures for every state, indexed by
+// year.
+type Dataset struct {
+	years map[int]map[string]int
 }
 
-// Get returns the population of the given state (by capitalized,
-// two-letter state code), or 0 and an error if the code is invalid.
-func Get(state string) (int, error) {
-	if pop, ok := populations[state]; ok {
-		return pop, nil
+// Years returns every year the dataset has figures for, in ascending
+// order.
+func (d *Dataset) Years() []int {
+	out := make([]int, 0, len(d.years))
+	for year := range d.years {
+		out = append(out, year)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// bracket returns the latest year no later than the given one and
+// the earliest no earlier than it that the dataset has figures for the
+// state in, which are the same if it has the year itself.  lowOK and
+// highOK report whether there's any such year on either side.
+func (d *Dataset) bracket(
+	state string,
+	year int,
+) (below, above int, lowOK, highOK bool) {
+	for _, y := range d.Years() {
+		if _, ok := d.years[y][state]; !ok {
+			continue
+		}
+		if y <= year {
+			below, lowOK = y, true
+		}
+		if y >= year && !highOK {
+			above, highOK = y, true
+		}
+	}
+	return
+}
+
+// merge returns a new dataset with the figures from both d and other,
+// taking other's for any year they both have.
+func (d *Dataset) merge(other *Dataset) *Dataset {
+	out := &Dataset{years: map[int]map[string]int{}}
+	for _, dataset := range []*Dataset{d, other} {
+		for year, figures := range dataset.years {
+			out.years[year] = figures
+		}
+	}
+	return out
+}
+
+// Get returns the population of the given state in the given year,
+// or 0 and an error if the code is invalid.  Years between those with
+// figures for the state are linearly interpolated from the figures
+// either side of them, skipping over any years missing the state, and
+// years outside that range get the nearest figures.
+func (d *Dataset) Get(state string, year int) (int, error) {
+	below, above, lowOK, highOK := d.bracket(state, year)
+	low, high := d.years[below][state], d.years[above][state]
+
+	switch {
+	case lowOK && highOK && above != below:
+		return low + (high-low)*(year-below)/(above-below), nil
+	case lowOK:
+		return low, nil
+	case highOK:
+		return high, nil
 	}
 	return 0, errors.New("State not found")
 }
 
+// Basis selects which population figures to weight votes by.
+type Basis string
+
+// The available bases.  Only Total figures are built in; the rest
+// have to be loaded with LoadFile before they can be used.
+const (
+	Total            Basis = "total"
+	VotingAge        Basis = "voting-age"
+	CitizenVotingAge Basis = "citizen-voting-age"
+	Registered       Basis = "registered"
+)
+
+// Bases lists every basis, in order of inclusiveness.
+var Bases = []Basis{Total, VotingAge, CitizenVotingAge, Registered}
+
+// ParseBasis returns the basis with the given name, or an error if
+// there's no such basis.  An empty name selects Total.
+func ParseBasis(name string) (Basis, error) {
+	if name == "" {
+		return Total, nil
+	}
+	for _, basis := range Bases {
+		if string(basis) == name {
+			return basis, nil
+		}
+	}
+	return "", fmt.Errorf("census: Unknown basis %q", name)
+}
+
+// ErrBasisUnavailable signals a lookup for a basis whose figures
+// haven't been loaded.
+var ErrBasisUnavailable = errors.New("No figures loaded for that basis")
+
+// datasetsMutex guards datasets, which LoadFile can change at any
+// time.
+var datasetsMutex = sync.RWMutex{}
+var datasets = map[Basis]*Dataset{
+	Total: totals,
+}
+
+// builtIn holds the figures compiled in for each basis, which figures
+// loaded with LoadFile are merged over.
+var builtIn = map[Basis]*Dataset{
+	Total: totals,
+}
+
+// Available reports whether figures have been loaded for the given
+// basis.
+func Available(basis Basis) bool {
+	datasetsMutex.RLock()
+	defer datasetsMutex.RUnlock()
+	return datasets[basis] != nil
+}
+
+// Get returns the most recent population of the given state (by
+// capitalized, two-letter state code), or 0 and an error if the code
+// is invalid.
+func Get(state string) (int, error) {
+	datasetsMutex.RLock()
+	defer datasetsMutex.RUnlock()
+
+	dataset := datasets[Total]
+	years := dataset.Years()
+	return dataset.Get(state, years[len(years)-1])
+}
+
+// GetAt returns the population of the given state in the year of the
+// given time, linearly interpolated between the census counts or
+// estimates either side of it (as by Dataset.Get), or 0 and an error
+// if the code is invalid.
+func GetAt(state string, t time.Time) (int, error) {
+	return GetBasisAt(Total, state, t)
+}
+
+// GetBasisAt is like GetAt, but returns figures for the given basis,
+// or ErrBasisUnavailable if none have been loaded.
+func GetBasisAt(basis Basis, state string, t time.Time) (int, error) {
+	datasetsMutex.RLock()
+	defer datasetsMutex.RUnlock()
+
+	dataset := datasets[basis]
+	if dataset == nil {
+		return 0, ErrBasisUnavailable
+	}
+	return dataset.Get(state, t.Year())
+}
+
 // AllStates returns a full list of available state codes.
 func AllStates() []string {
-	out := make([]string, len(populations))
+	out := make([]string, len(stateNames))
 
 	i := 0
-	for k := range populations {
+	for k := range stateNames {
 		out[i] = k
 		i++
 	}
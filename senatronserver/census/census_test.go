@@ -0,0 +1,86 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"testing"
+)
+
+func TestDatasetGet(t *testing.T) {
+	dataset := &Dataset{years: map[int]map[string]int{
+		1990: {"CA": 1000, "PR": 50},
+		2000: {"CA": 2000},
+		2010: {"CA": 4000, "PR": 70},
+	}}
+
+	cases := []struct {
+		state    string
+		year     int
+		expected int
+	}{
+		{"CA", 1980, 1000},
+		{"CA", 1990, 1000},
+		{"CA", 1995, 1500},
+		{"CA", 1999, 1900},
+		{"CA", 2000, 2000},
+		{"CA", 2004, 2800},
+		{"CA", 2020, 4000},
+		{"PR", 1990, 50},
+		{"PR", 1995, 55},
+		{"PR", 2000, 60},
+		{"PR", 2005, 65},
+		{"PR", 2015, 70},
+	}
+	for _, c := range cases {
+		population, err := dataset.Get(c.state, c.year)
+		if err != nil {
+			t.Errorf("Get(%q, %d): %v", c.state, c.year, err)
+		} else if population != c.expected {
+			t.Errorf(
+				"Get(%q, %d) = %d, expected %d",
+				c.state,
+				c.year,
+				population,
+				c.expected,
+			)
+		}
+	}
+
+	if _, err := dataset.Get("XX", 2000); err == nil {
+		t.Errorf("Get succeeded for an unknown state")
+	}
+}
+
+func TestBuiltInTotalsInterpolate(t *testing.T) {
+	at1990, _ := totals.Get("CA", 1990)
+	at2000, _ := totals.Get("CA", 2000)
+	at1995, err := totals.Get("CA", 1995)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at1995 <= at1990 || at1995 >= at2000 {
+		t.Errorf(
+			"1995 figure %d isn't between 1990's %d and 2000's %d",
+			at1995,
+			at1990,
+			at2000,
+		)
+	}
+}
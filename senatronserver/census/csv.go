@@ -0,0 +1,294 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// plainYearColumn matches the headers of columns in the formatted
+// tables, which are just the year ("2014").
+var plainYearColumn = regexp.MustCompile(`^(\d{4})$`)
+
+// yearColumnPatterns lists, for each basis, the patterns matching the
+// headers of columns holding that basis's figures for a single year,
+// in order of preference.  Alongside the formatted tables' plain
+// years, the machine-readable "alldata" files name their columns
+// "CENSUS2010POP" and "POPESTIMATE2014" for total population, or
+// "POPEST18PLUS2014" for the voting-age population (in files that
+// have the total right next to it).
+var yearColumnPatterns = map[Basis][]*regexp.Regexp{
+	Total: {
+		regexp.MustCompile(`^CENSUS(\d{4})POP$`),
+		regexp.MustCompile(`^POPESTIMATE(\d{4})$`),
+		plainYearColumn,
+	},
+	VotingAge: {
+		regexp.MustCompile(`^POPEST18PLUS(\d{4})$`),
+		plainYearColumn,
+	},
+	CitizenVotingAge: {plainYearColumn},
+	Registered:       {plainYearColumn},
+}
+
+// ReadCSV parses population figures for the given basis from a Census
+// Bureau CSV file.  Both the formatted tables (like NST-EST2014-01.csv,
+// with state names in the first column and a header row of years) and
+// the "alldata" files (with a NAME column and POPESTIMATEyyyy or
+// POPEST18PLUSyyyy columns) are understood.  Rows that aren't states,
+// like regions or the national total, are skipped.  Every state has to
+// be present, with positive figures, for every year in the file,
+// although territories are optional since plenty of datasets (voter
+// registration surveys, for instance) don't cover them.
+func ReadCSV(r io.Reader, basis Basis) (*Dataset, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	nameColumn := 0
+	yearColumns := map[int]int{}
+	dataset := &Dataset{years: map[int]map[string]int{}}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(yearColumns) == 0 {
+			nameColumn, yearColumns, err = parseHeader(row, basis)
+			if err != nil {
+				return nil, err
+			}
+			for _, year := range yearColumns {
+				dataset.years[year] = map[string]int{}
+			}
+			continue
+		}
+
+		if nameColumn >= len(row) {
+			continue
+		}
+		code, ok := stateCode(row[nameColumn])
+		if !ok {
+			continue
+		}
+
+		for column, year := range yearColumns {
+			if column >= len(row) {
+				return nil, fmt.Errorf(
+					"census: No %d figure for %s",
+					year,
+					code,
+				)
+			}
+
+			population, err := parseFigure(row[column], year, code)
+			if err != nil {
+				return nil, err
+			}
+			dataset.years[year][code] = population
+		}
+	}
+
+	if len(yearColumns) == 0 {
+		return nil, fmt.Errorf("census: No year columns found")
+	}
+
+	err := dataset.validate()
+	if err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
+
+// parseFigure reads the given year's figure for the given state (or
+// district) from a cell, which has to be a positive number, although
+// it can have commas in it.
+func parseFigure(cell string, year int, code string) (int, error) {
+	population, err := strconv.Atoi(
+		strings.Replace(strings.TrimSpace(cell), ",", "", -1),
+	)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"census: Bad %d figure for %s: %q",
+			year,
+			code,
+			cell,
+		)
+	}
+	if population <= 0 {
+		return 0, fmt.Errorf(
+			"census: Non-positive %d figure for %s: %d",
+			year,
+			code,
+			population,
+		)
+	}
+	return population, nil
+}
+
+// parseHeader checks whether a row is the header row, returning the
+// index of the column holding state names and a map from the indices
+// of the given basis's year columns to their years.  If the row isn't
+// a header, the returned map is empty.  Where a file has more than one
+// column for a year (say, a census count and an estimate), the one
+// whose pattern comes first in yearColumnPatterns wins, and it's an
+// error for there to be a tie.
+func parseHeader(
+	row []string,
+	basis Basis,
+) (nameColumn int, yearColumns map[int]int, err error) {
+	patterns := yearColumnPatterns[basis]
+	yearColumns = map[int]int{}
+	columns := map[int]int{}
+	preferences := map[int]int{}
+
+	for i, cell := range row {
+		cell = strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))
+		if cell == "NAME" {
+			nameColumn = i
+			continue
+		}
+
+		for preference, pattern := range patterns {
+			matches := pattern.FindStringSubmatch(cell)
+			if matches == nil {
+				continue
+			}
+			year, _ := strconv.Atoi(matches[1])
+
+			best, seen := preferences[year]
+			if seen && best == preference {
+				err = fmt.Errorf(
+					"census: More than one %s column for %d",
+					basis,
+					year,
+				)
+				return
+			} else if !seen || preference < best {
+				columns[year] = i
+				preferences[year] = preference
+			}
+			break
+		}
+	}
+
+	for year, column := range columns {
+		yearColumns[column] = year
+	}
+	return
+}
+
+// stateCode looks up the state code for a cell that names a state,
+// either by code or by name.  The formatted tables prefix state names
+// with a dot, so that's ignored.
+func stateCode(cell string) (string, bool) {
+	cell = strings.TrimPrefix(strings.TrimSpace(cell), ".")
+	if _, ok := stateNames[cell]; ok {
+		return cell, true
+	}
+	code, ok := stateCodes[cell]
+	return code, ok
+}
+
+// validate makes sure that the dataset has a figure for every state
+// (other than territories) in every year.
+func (d *Dataset) validate() error {
+	for _, year := range d.Years() {
+		missing := []string{}
+		for code := range stateNames {
+			if nonStates[code] == Territory {
+				continue
+			}
+			if _, ok := d.years[year][code]; !ok {
+				missing = append(missing, code)
+			}
+		}
+
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf(
+				"census: No %d figures for %s",
+				year,
+				strings.Join(missing, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// LoadFile reads population figures for the given basis from a Census
+// Bureau CSV file (as described for ReadCSV) and, if they're valid,
+// replaces any previously loaded figures for that basis with them.
+// Built-in figures are kept for any years the file doesn't cover, so
+// loading recent estimates doesn't lose the older census counts.  On
+// error the existing figures are left alone, so it's safe to use for
+// reloading at runtime.
+func LoadFile(basis Basis, path string) error {
+	return LoadFiles(basis, path)
+}
+
+// LoadFiles is like LoadFile, but reads figures from several files at
+// once, like the Census Bureau's annual estimates for each decade.
+// Where files cover the same year, the later file's figures win.
+func LoadFiles(basis Basis, paths ...string) error {
+	dataset := builtIn[basis]
+	for _, path := range paths {
+		loaded, err := readCSVFile(basis, path)
+		if err != nil {
+			return err
+		}
+		if dataset == nil {
+			dataset = loaded
+		} else {
+			dataset = dataset.merge(loaded)
+		}
+	}
+
+	datasetsMutex.Lock()
+	defer datasetsMutex.Unlock()
+	datasets[basis] = dataset
+	return nil
+}
+
+// readCSVFile reads figures for the given basis from a single file.
+func readCSVFile(basis Basis, path string) (*Dataset, error) {
+	fin, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fin.Close()
+
+	dataset, err := ReadCSV(fin, basis)
+	if err != nil {
+		return nil, fmt.Errorf("%v (in %s)", err, path)
+	}
+	return dataset, nil
+}
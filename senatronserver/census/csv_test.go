@@ -0,0 +1,280 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCSV builds a CSV file with the given header, and a row for every
+// state and DC whose figures come from the given function of its code
+// and column.
+func testCSV(
+	header []string,
+	nameColumn int,
+	figure func(code string, column int) int,
+) string {
+	codes := make([]string, 0, len(stateNames))
+	for code := range stateNames {
+		if nonStates[code] != Territory {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	rows := []string{strings.Join(header, ",")}
+	for _, code := range codes {
+		row := make([]string, len(header))
+		for i := range header {
+			if i == nameColumn {
+				row[i] = "." + stateNames[code]
+			} else {
+				row[i] = fmt.Sprint(figure(code, i))
+			}
+		}
+		rows = append(rows, strings.Join(row, ","))
+	}
+	return strings.Join(rows, "\n") + "\n"
+}
+
+// withCSVFile writes contents to a temporary file and calls fn with
+// its path, restoring the loaded datasets afterwards.
+func withCSVFile(t *testing.T, contents string, fn func(path string)) {
+	withCSVFiles(
+		t,
+		[]string{contents},
+		func(paths []string) { fn(paths[0]) },
+	)
+}
+
+// withCSVFiles is like withCSVFile, but writes a temporary file for
+// each of the given contents.
+func withCSVFiles(t *testing.T, contents []string, fn func(paths []string)) {
+	paths := make([]string, len(contents))
+	for i, c := range contents {
+		fout, err := ioutil.TempFile("", "census")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(fout.Name())
+		_, err = fout.WriteString(c)
+		fout.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[i] = fout.Name()
+	}
+
+	saved := map[Basis]*Dataset{}
+	for basis, dataset := range datasets {
+		saved[basis] = dataset
+	}
+	savedDistricts := districts
+	defer func() {
+		datasets = saved
+		districts = savedDistricts
+	}()
+
+	fn(paths)
+}
+
+func TestLoadFileKeepsBuiltInYears(t *testing.T) {
+	contents := testCSV(
+		[]string{"Geographic Area", "2010", "2011", "2012", "2013", "2014"},
+		0,
+		func(code string, column int) int { return 1000 + column },
+	)
+
+	withCSVFile(t, contents, func(path string) {
+		at := func(year int) time.Time {
+			return time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC)
+		}
+		before, _ := GetAt("CA", at(1995))
+
+		err := LoadFile(Total, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		after, _ := GetAt("CA", at(1995))
+		if after != before {
+			t.Errorf("1995 figure changed from %d to %d", before, after)
+		}
+		loaded, _ := GetAt("CA", at(2012))
+		if loaded != 1003 {
+			t.Errorf("2012 figure is %d, expected the loaded 1003", loaded)
+		}
+	})
+}
+
+func TestLoadFilesMergesDecades(t *testing.T) {
+	figure := func(code string, column int) int { return 1000 + column }
+	contents := []string{
+		testCSV(
+			[]string{"NAME", "POPESTIMATE2000", "POPESTIMATE2001", "POPESTIMATE2010"},
+			0,
+			figure,
+		),
+		testCSV(
+			[]string{"NAME", "POPESTIMATE2010", "POPESTIMATE2011"},
+			0,
+			func(code string, column int) int { return 2000 + column },
+		),
+	}
+
+	withCSVFiles(t, contents, func(paths []string) {
+		err := LoadFiles(Total, paths...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			year     int
+			expected int
+		}{
+			{2001, 1002},
+			{2010, 2001},
+			{2011, 2002},
+		}
+		for _, c := range cases {
+			at := time.Date(c.year, time.July, 1, 0, 0, 0, 0, time.UTC)
+			population, _ := GetAt("CA", at)
+			if population != c.expected {
+				t.Errorf("%d figure is %d, expected %d", c.year, population, c.expected)
+			}
+		}
+
+		// The built-in census counts are still there.
+		at1990, _ := GetAt("CA", time.Date(1990, time.April, 1, 0, 0, 0, 0, time.UTC))
+		if at1990 != 29760021 {
+			t.Errorf("1990 figure is %d", at1990)
+		}
+	})
+}
+
+func TestReadCSVPicksBasisColumns(t *testing.T) {
+	// The figures in each column are its index, times a thousand.
+	cases := []struct {
+		name     string
+		header   []string
+		basis    Basis
+		expected map[int]int
+		fails    bool
+	}{
+		{
+			"formatted total",
+			[]string{"Geographic Area", "2013", "2014"},
+			Total,
+			map[int]int{2013: 1000, 2014: 2000},
+			false,
+		},
+		{
+			"18+ file as total",
+			[]string{
+				"SUMLEV", "STATE", "NAME", "POPESTIMATE2014",
+				"POPEST18PLUS2014", "PCNT_POPEST18PLUS",
+			},
+			Total,
+			map[int]int{2014: 3000},
+			false,
+		},
+		{
+			"18+ file as voting age",
+			[]string{
+				"SUMLEV", "STATE", "NAME", "POPESTIMATE2014",
+				"POPEST18PLUS2014", "PCNT_POPEST18PLUS",
+			},
+			VotingAge,
+			map[int]int{2014: 4000},
+			false,
+		},
+		{
+			"alldata prefers census counts",
+			[]string{
+				"SUMLEV", "NAME", "CENSUS2010POP", "ESTIMATESBASE2010",
+				"POPESTIMATE2010", "POPESTIMATE2011",
+			},
+			Total,
+			map[int]int{2010: 2000, 2011: 5000},
+			false,
+		},
+		{
+			"alldata as voting age",
+			[]string{"SUMLEV", "NAME", "CENSUS2010POP", "POPESTIMATE2010"},
+			VotingAge,
+			nil,
+			true,
+		},
+		{
+			"ambiguous years",
+			[]string{"Geographic Area", "2014", "2014"},
+			Registered,
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		nameColumn := 0
+		for i, cell := range c.header {
+			if cell == "NAME" {
+				nameColumn = i
+			}
+		}
+		contents := testCSV(
+			c.header,
+			nameColumn,
+			func(code string, column int) int { return column * 1000 },
+		)
+
+		dataset, err := ReadCSV(strings.NewReader(contents), c.basis)
+		if c.fails {
+			if err == nil {
+				t.Errorf("%s: Expected an error", c.name)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+
+		if len(dataset.years) != len(c.expected) {
+			t.Errorf("%s: Got years %v", c.name, dataset.Years())
+		}
+		for year, expected := range c.expected {
+			population, _ := dataset.Get("CA", year)
+			if population != expected {
+				t.Errorf(
+					"%s: Got %d for %d, expected %d",
+					c.name,
+					population,
+					year,
+					expected,
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+// totals holds resident population counts from the decennial census
+// (taken on April 1st of 1990, 2000 and 2010), plus the 2014 estimate.
+// Dataset.Get interpolates the years in between; loading the Census
+// Bureau's annual estimates with LoadFiles replaces that with the real
+// figures for the years they cover.
+var totals = &Dataset{years: map[int]map[string]int{
+	// 1990 Census resident population
+	1990: {
+		"AK": 550043,
+		"AL": 4040587,
+		"AR": 2350725,
+		"AZ": 3665228,
+		"CA": 29760021,
+		"CO": 3294394,
+		"CT": 3287116,
+		"DC": 606900,
+		"DE": 666168,
+		"FL": 12937926,
+		"GA": 6478216,
+		"HI": 1108229,
+		"IA": 2776755,
+		"ID": 1006749,
+		"IL": 11430602,
+		"IN": 5544159,
+		"KS": 2477574,
+		"KY": 3685296,
+		"LA": 4219973,
+		"MA": 6016425,
+		"MD": 4781468,
+		"ME": 1227928,
+		"MI": 9295297,
+		"MN": 4375099,
+		"MO": 5117073,
+		"MS": 2573216,
+		"MT": 799065,
+		"NC": 6628637,
+		"ND": 638800,
+		"NE": 1578385,
+		"NH": 1109252,
+		"NJ": 7730188,
+		"NM": 1515069,
+		"NV": 1201833,
+		"NY": 17990455,
+		"OH": 10847115,
+		"OK": 3145585,
+		"OR": 2842321,
+		"PA": 11881643,
+		"PR": 3522037,
+		"RI": 1003464,
+		"SC": 3486703,
+		"SD": 696004,
+		"TN": 4877185,
+		"TX": 16986510,
+		"UT": 1722850,
+		"VA": 6187358,
+		"VT": 562758,
+		"WA": 4866692,
+		"WI": 4891769,
+		"WV": 1793477,
+		"WY": 453588,
+	},
+	// Census 2000 resident population
+	2000: {
+		"AK": 626932,
+		"AL": 4447100,
+		"AR": 2673400,
+		"AZ": 5130632,
+		"CA": 33871648,
+		"CO": 4301261,
+		"CT": 3405565,
+		"DC": 572059,
+		"DE": 783600,
+		"FL": 15982378,
+		"GA": 8186453,
+		"HI": 1211537,
+		"IA": 2926324,
+		"ID": 1293953,
+		"IL": 12419293,
+		"IN": 6080485,
+		"KS": 2688418,
+		"KY": 4041769,
+		"LA": 4468976,
+		"MA": 6349097,
+		"MD": 5296486,
+		"ME": 1274923,
+		"MI": 9938444,
+		"MN": 4919479,
+		"MO": 5595211,
+		"MS": 2844658,
+		"MT": 902195,
+		"NC": 8049313,
+		"ND": 642200,
+		"NE": 1711263,
+		"NH": 1235786,
+		"NJ": 8414350,
+		"NM": 1819046,
+		"NV": 1998257,
+		"NY": 18976457,
+		"OH": 11353140,
+		"OK": 3450654,
+		"OR": 3421399,
+		"PA": 12281054,
+		"PR": 3808610,
+		"RI": 1048319,
+		"SC": 4012012,
+		"SD": 754844,
+		"TN": 5689283,
+		"TX": 20851820,
+		"UT": 2233169,
+		"VA": 7078515,
+		"VT": 608827,
+		"WA": 5894121,
+		"WI": 5363675,
+		"WV": 1808344,
+		"WY": 493782,
+	},
+	// 2010 Census resident population
+	2010: {
+		"AK": 710231,
+		"AL": 4779736,
+		"AR": 2915918,
+		"AZ": 6392017,
+		"CA": 37253956,
+		"CO": 5029196,
+		"CT": 3574097,
+		"DC": 601723,
+		"DE": 897934,
+		"FL": 18801310,
+		"GA": 9687653,
+		"HI": 1360301,
+		"IA": 3046355,
+		"ID": 1567582,
+		"IL": 12830632,
+		"IN": 6483802,
+		"KS": 2853118,
+		"KY": 4339367,
+		"LA": 4533372,
+		"MA": 6547629,
+		"MD": 5773552,
+		"ME": 1328361,
+		"MI": 9883640,
+		"MN": 5303925,
+		"MO": 5988927,
+		"MS": 2967297,
+		"MT": 989415,
+		"NC": 9535483,
+		"ND": 672591,
+		"NE": 1826341,
+		"NH": 1316470,
+		"NJ": 8791894,
+		"NM": 2059179,
+		"NV": 2700551,
+		"NY": 19378102,
+		"OH": 11536504,
+		"OK": 3751351,
+		"OR": 3831074,
+		"PA": 12702379,
+		"PR": 3725789,
+		"RI": 1052567,
+		"SC": 4625364,
+		"SD": 814180,
+		"TN": 6346105,
+		"TX": 25145561,
+		"UT": 2763885,
+		"VA": 8001024,
+		"VT": 625741,
+		"WA": 6724540,
+		"WI": 5686986,
+		"WV": 1852994,
+		"WY": 563626,
+	},
+	// July 1st 2014 estimates from
+	// www.census.gov/popest/data/state/totals/2014/tables/NST-EST2014-01.csv
+	2014: {
+		"AK": 736732,
+		"AL": 4849377,
+		"AR": 2966369,
+		"AZ": 6731484,
+		"CA": 38802500,
+		"CO": 5355866,
+		"CT": 3596677,
+		"DC": 658893,
+		"DE": 935614,
+		"FL": 19893297,
+		"GA": 10097343,
+		"HI": 1419561,
+		"IA": 3107126,
+		"ID": 1634464,
+		"IL": 12880580,
+		"IN": 6596855,
+		"KS": 2904021,
+		"KY": 4413457,
+		"LA": 4649676,
+		"MA": 6745408,
+		"MD": 5976407,
+		"ME": 1330089,
+		"MI": 9909877,
+		"MN": 5457173,
+		"MO": 6063589,
+		"MS": 2994079,
+		"MT": 1023579,
+		"NC": 9943964,
+		"ND": 739482,
+		"NE": 1881503,
+		"NH": 1326813,
+		"NJ": 8938175,
+		"NM": 2085572,
+		"NV": 2839099,
+		"NY": 19746227,
+		"OH": 11594163,
+		"OK": 3878051,
+		"OR": 3970239,
+		"PA": 12787209,
+		"PR": 3548397,
+		"RI": 1055173,
+		"SC": 4832482,
+		"SD": 853175,
+		"TN": 6549352,
+		"TX": 26956958,
+		"UT": 2942902,
+		"VA": 8326289,
+		"VT": 626562,
+		"WA": 7061530,
+		"WI": 5757564,
+		"WV": 1850326,
+		"WY": 584153,
+	},
+}}
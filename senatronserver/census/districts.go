@@ -0,0 +1,367 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDistrictNotFound signals a lookup for a congressional district
+// that its state didn't have, or that there are no figures for, under
+// the apportionment in effect at the time.
+var ErrDistrictNotFound = errors.New("No figures for that district")
+
+// builtInDistricts holds the census-day population of every
+// congressional district, for each apportionment cycle whose census
+// count is built in (see censusDayDistricts).
+var builtInDistricts = censusDayDistricts()
+
+// districts holds the populations of congressional districts for each
+// apportionment cycle, keyed by the cycle's census year.  Each
+// dataset is keyed by district code (see districtCode) rather than
+// state.  Like datasets, it's guarded by datasetsMutex.
+var districts = builtInDistricts
+
+// districtCode names a congressional district, like "CA-12", with
+// "00" for at-large seats (as the Census Bureau numbers them).
+func districtCode(state string, district int) string {
+	return fmt.Sprintf("%s-%02d", state, district)
+}
+
+// districtNumbers returns the numbers of a state's districts given its
+// number of seats: 0 alone for a single at-large seat, and 1 up to the
+// number of seats otherwise.
+func districtNumbers(seats int) []int {
+	if seats == 1 {
+		return []int{0}
+	}
+	out := make([]int, seats)
+	for i := range out {
+		out[i] = i + 1
+	}
+	return out
+}
+
+// Districts returns the numbers of the congressional districts the
+// given state had at the given time, with 0 for a single at-large
+// seat, or ErrNoSeats if the code isn't a state.
+func Districts(state string, t time.Time) ([]int, error) {
+	seats, err := SeatsAt(state, t)
+	if err != nil {
+		return nil, err
+	}
+	return districtNumbers(seats), nil
+}
+
+// censusDayDistricts works out the population of every district on
+// the day of each census whose count is built in, which is when
+// they're drawn for the following decade.  The law requires a state's
+// districts to be as equal as practicable, and in practice they're
+// drawn to within a person or so of each other, so each district gets
+// its state's count divided by its seats, with any remainder going a
+// person apiece to the lowest-numbered districts.
+func censusDayDistricts() map[int]*Dataset {
+	out := map[int]*Dataset{}
+	for cycle, apportionment := range apportionments {
+		counts, ok := totals.years[cycle]
+		if !ok {
+			continue
+		}
+
+		figures := map[string]int{}
+		for state, seats := range apportionment {
+			count, ok := counts[state]
+			if !ok {
+				continue
+			}
+			for i, district := range districtNumbers(seats) {
+				population := count / seats
+				if i < count%seats {
+					population++
+				}
+				figures[districtCode(state, district)] = population
+			}
+		}
+		out[cycle] = &Dataset{years: map[int]map[string]int{cycle: figures}}
+	}
+	return out
+}
+
+// GetDistrictAt returns the population of the given congressional
+// district (numbered as by Districts) in the year of the given time.
+// Only figures for districts as drawn for the apportionment in effect
+// at the time are used, since the same number names a different
+// place once districts have been redrawn, so between estimates (or
+// after the last one) figures are interpolated as by Dataset.Get from
+// within the same cycle.  It returns ErrDistrictNotFound if there are
+// no figures for the district.
+func GetDistrictAt(state string, district int, t time.Time) (int, error) {
+	cycle := ApportionmentYear(t)
+
+	datasetsMutex.RLock()
+	defer datasetsMutex.RUnlock()
+
+	dataset, ok := districts[cycle]
+	if !ok {
+		return 0, ErrDistrictNotFound
+	}
+	population, err := dataset.Get(districtCode(state, district), t.Year())
+	if err != nil {
+		return 0, ErrDistrictNotFound
+	}
+	return population, nil
+}
+
+// congressStart returns when the given congress convened, or at least
+// when modern ones do: January 3rd of the year after an election.
+func congressStart(congress int) time.Time {
+	year := 1789 + 2*(congress-1)
+	return time.Date(year, time.January, 3, 0, 0, 0, 0, time.UTC)
+}
+
+// ReadDistrictCSV parses congressional district populations from a
+// CSV file, like one put together from the Census Bureau's American
+// Community Survey estimates for each district.  It needs a header
+// row with STATE (by name or code), DISTRICT (with 0, 00 or "At
+// Large" for at-large seats) and CONGRESS columns, and a column of
+// figures for each year headed by the year itself ("2014").  The
+// districts in each row are the ones drawn for the given congress, and
+// the figures are grouped by the apportionment cycle it fell in.  For
+// each cycle and year in the file, every district of every state has
+// to be present with a positive figure.
+func ReadDistrictCSV(r io.Reader) (map[int]*Dataset, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var columns map[string]int
+	yearColumns := map[int]int{}
+	cycles := map[int]*Dataset{}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if columns == nil {
+			columns, yearColumns = parseDistrictHeader(row)
+			continue
+		}
+
+		cells := map[string]string{}
+		for name, column := range columns {
+			if column < len(row) {
+				cells[name] = strings.TrimSpace(row[column])
+			}
+		}
+		state, ok := stateCode(cells["STATE"])
+		if !ok {
+			continue
+		}
+		district, err := parseDistrict(cells["DISTRICT"])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"census: Bad district for %s: %q",
+				state,
+				cells["DISTRICT"],
+			)
+		}
+		congress, err := strconv.Atoi(cells["CONGRESS"])
+		if err != nil || congress <= 0 {
+			return nil, fmt.Errorf(
+				"census: Bad congress for %s: %q",
+				districtCode(state, district),
+				cells["CONGRESS"],
+			)
+		}
+
+		cycle := ApportionmentYear(congressStart(congress))
+		dataset, ok := cycles[cycle]
+		if !ok {
+			dataset = &Dataset{years: map[int]map[string]int{}}
+			for _, year := range yearColumns {
+				dataset.years[year] = map[string]int{}
+			}
+			cycles[cycle] = dataset
+		}
+
+		code := districtCode(state, district)
+		for column, year := range yearColumns {
+			cell := ""
+			if column < len(row) {
+				cell = row[column]
+			}
+			population, err := parseFigure(cell, year, code)
+			if err != nil {
+				return nil, err
+			}
+			dataset.years[year][code] = population
+		}
+	}
+
+	if columns == nil {
+		return nil, fmt.Errorf("census: No district header row found")
+	}
+
+	for cycle, dataset := range cycles {
+		err := validateDistricts(cycle, dataset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cycles, nil
+}
+
+// parseDistrictHeader checks whether a row is the header of a district
+// file, returning the indices of its STATE, DISTRICT and CONGRESS
+// columns along with a map from the indices of its year columns to
+// their years.  If the row isn't a header, both maps are nil.
+func parseDistrictHeader(
+	row []string,
+) (columns map[string]int, yearColumns map[int]int) {
+	columns = map[string]int{}
+	yearColumns = map[int]int{}
+	for i, cell := range row {
+		cell = strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))
+		switch cell {
+		case "STATE", "DISTRICT", "CONGRESS":
+			columns[cell] = i
+			continue
+		}
+		if matches := plainYearColumn.FindStringSubmatch(cell); matches != nil {
+			year, _ := strconv.Atoi(matches[1])
+			yearColumns[i] = year
+		}
+	}
+
+	if len(columns) < 3 || len(yearColumns) == 0 {
+		return nil, nil
+	}
+	return columns, yearColumns
+}
+
+// parseDistrict reads a district number, with 0 (or "At Large") for
+// at-large seats.
+func parseDistrict(cell string) (int, error) {
+	if strings.Contains(strings.ToLower(cell), "at large") {
+		return 0, nil
+	}
+	district, err := strconv.Atoi(cell)
+	if err == nil && district < 0 {
+		err = fmt.Errorf("census: Negative district %d", district)
+	}
+	return district, err
+}
+
+// validateDistricts makes sure that a cycle's dataset has a figure for
+// every district of every state in every year, and no others.
+func validateDistricts(cycle int, dataset *Dataset) error {
+	expected := map[string]bool{}
+	for state, seats := range apportionments[cycle] {
+		for _, district := range districtNumbers(seats) {
+			expected[districtCode(state, district)] = true
+		}
+	}
+
+	for _, year := range dataset.Years() {
+		missing := []string{}
+		for code := range expected {
+			if _, ok := dataset.years[year][code]; !ok {
+				missing = append(missing, code)
+			}
+		}
+		for code := range dataset.years[year] {
+			if !expected[code] {
+				return fmt.Errorf(
+					"census: No district %s in the %d apportionment",
+					code,
+					cycle,
+				)
+			}
+		}
+
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf(
+				"census: No %d figures for %s",
+				year,
+				strings.Join(missing, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// LoadDistrictFiles reads congressional district populations from CSV
+// files (as described for ReadDistrictCSV) and, if they're all valid,
+// replaces any previously loaded district figures with them.  They're
+// merged over the built-in census-day figures in the order given, so
+// where files cover the same cycle and year the later one wins.  On
+// error the existing figures are left alone.
+func LoadDistrictFiles(paths ...string) error {
+	loaded := map[int]*Dataset{}
+	for cycle, dataset := range builtInDistricts {
+		loaded[cycle] = dataset
+	}
+
+	for _, path := range paths {
+		cycles, err := readDistrictFile(path)
+		if err != nil {
+			return err
+		}
+		for cycle, dataset := range cycles {
+			if existing, ok := loaded[cycle]; ok {
+				dataset = existing.merge(dataset)
+			}
+			loaded[cycle] = dataset
+		}
+	}
+
+	datasetsMutex.Lock()
+	defer datasetsMutex.Unlock()
+	districts = loaded
+	return nil
+}
+
+// readDistrictFile reads district figures from a single file.
+func readDistrictFile(path string) (map[int]*Dataset, error) {
+	fin, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fin.Close()
+
+	cycles, err := ReadDistrictCSV(fin)
+	if err != nil {
+		return nil, fmt.Errorf("%v (in %s)", err, path)
+	}
+	return cycles, nil
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// inCycle returns a time in the middle of the given year, which falls
+// in the apportionment cycle of the census before it.
+func inCycle(year int) time.Time {
+	return time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDistricts(t *testing.T) {
+	cases := []struct {
+		state    string
+		year     int
+		expected []int
+	}{
+		{"WY", 2014, []int{0}},
+		{"MT", 1992, []int{1, 2}},
+		{"MT", 1994, []int{0}},
+		{"NV", 2014, []int{1, 2, 3, 4}},
+	}
+	for _, c := range cases {
+		districts, err := Districts(c.state, inCycle(c.year))
+		if err != nil {
+			t.Errorf("%s in %d: %v", c.state, c.year, err)
+		} else if !reflect.DeepEqual(districts, c.expected) {
+			t.Errorf(
+				"%s in %d has districts %v, expected %v",
+				c.state,
+				c.year,
+				districts,
+				c.expected,
+			)
+		}
+	}
+
+	_, err := Districts("DC", inCycle(2014))
+	if err != ErrNoSeats {
+		t.Errorf("Got %v for DC, expected ErrNoSeats", err)
+	}
+}
+
+func TestGetDistrictAtCensusDay(t *testing.T) {
+	// Each state's census count is shared out between its districts.
+	for _, cycle := range []int{1990, 2000, 2010} {
+		for state, seats := range apportionments[cycle] {
+			count := totals.years[cycle][state]
+			sum := 0
+			for _, district := range districtNumbers(seats) {
+				population, err := GetDistrictAt(
+					state,
+					district,
+					inCycle(cycle+5),
+				)
+				if err != nil {
+					t.Fatalf("%s in %d: %v", districtCode(state, district), cycle, err)
+				}
+				if population < count/seats || population > count/seats+1 {
+					t.Errorf(
+						"%s in %d has %d people, expected about %d",
+						districtCode(state, district),
+						cycle,
+						population,
+						count/seats,
+					)
+				}
+				sum += population
+			}
+			if sum != count {
+				t.Errorf("%s's %d districts add up to %d, not %d", state, cycle, sum, count)
+			}
+		}
+	}
+
+	// There's no count for the 1980 census built in.
+	_, err := GetDistrictAt("CA", 1, inCycle(1985))
+	if err != ErrDistrictNotFound {
+		t.Errorf("Got %v for 1985, expected ErrDistrictNotFound", err)
+	}
+	for _, district := range []int{0, 54} {
+		_, err = GetDistrictAt("CA", district, inCycle(2014))
+		if err != ErrDistrictNotFound {
+			t.Errorf("Got %v for CA-%02d, expected ErrDistrictNotFound", err, district)
+		}
+	}
+}
+
+// testDistrictCSV returns a district file with a header row of the
+// given columns (after STATE, DISTRICT and CONGRESS), and a row for
+// each district of the 2010 apportionment, as drawn for the given
+// congress, with figures from figure.
+func testDistrictCSV(
+	columns []string,
+	congress int,
+	figure func(code string, column int) int,
+) string {
+	header := append([]string{"STATE", "DISTRICT", "CONGRESS"}, columns...)
+	lines := []string{strings.Join(header, ",")}
+	for state, seats := range apportionments[2010] {
+		for _, district := range districtNumbers(seats) {
+			code := districtCode(state, district)
+			cells := []string{
+				stateNames[state],
+				fmt.Sprintf("%02d", district),
+				fmt.Sprint(congress),
+			}
+			for i := range columns {
+				cells = append(cells, fmt.Sprint(figure(code, i)))
+			}
+			lines = append(lines, strings.Join(cells, ","))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLoadDistrictFiles(t *testing.T) {
+	contents := []string{
+		testDistrictCSV(
+			[]string{"2014", "2016"},
+			113,
+			func(code string, column int) int { return 700000 + column },
+		),
+		testDistrictCSV(
+			[]string{"2016"},
+			115,
+			func(code string, column int) int {
+				if code == "CA-12" {
+					return 900000
+				}
+				return 800000
+			},
+		),
+	}
+
+	withCSVFiles(t, contents, func(paths []string) {
+		censusDay, err := GetDistrictAt("CA", 12, inCycle(2013))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = LoadDistrictFiles(paths...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			district int
+			year     int
+			expected int
+		}{
+			{12, 2013, censusDay + (700000-censusDay)*3/4},
+			{12, 2014, 700000},
+			{12, 2015, 800000},
+			{12, 2016, 900000},
+			{12, 2018, 900000},
+			{11, 2016, 800000},
+		}
+		for _, c := range cases {
+			population, err := GetDistrictAt("CA", c.district, inCycle(c.year))
+			if err != nil {
+				t.Errorf("CA-%02d in %d: %v", c.district, c.year, err)
+			} else if population != c.expected {
+				t.Errorf(
+					"CA-%02d in %d has %d people, expected %d",
+					c.district,
+					c.year,
+					population,
+					c.expected,
+				)
+			}
+		}
+
+		// Earlier cycles are left alone.
+		if _, err := GetDistrictAt("CA", 12, inCycle(2005)); err != nil {
+			t.Errorf("Lost the 2000 cycle: %v", err)
+		}
+	})
+}
+
+var malformedDistrictCSVs = []struct {
+	name     string
+	contents string
+}{
+	{
+		name:     "no header",
+		contents: "California,12,113,700000\n",
+	},
+	{
+		name: "missing district",
+		contents: strings.Replace(
+			testDistrictCSV(
+				[]string{"2014"},
+				113,
+				func(code string, column int) int { return 700000 },
+			),
+			"\nWyoming,00,113,700000",
+			"",
+			1,
+		),
+	},
+	{
+		name: "extra district",
+		contents: testDistrictCSV(
+			[]string{"2014"},
+			113,
+			func(code string, column int) int { return 700000 },
+		) + "\nWyoming,01,113,700000",
+	},
+	{
+		name: "bad district",
+		contents: testDistrictCSV(
+			[]string{"2014"},
+			113,
+			func(code string, column int) int { return 700000 },
+		) + "\nWyoming,first,113,700000",
+	},
+	{
+		name: "bad figure",
+		contents: testDistrictCSV(
+			[]string{"2014"},
+			113,
+			func(code string, column int) int {
+				if code == "CA-12" {
+					return 0
+				}
+				return 700000
+			},
+		),
+	},
+}
+
+func TestLoadDistrictFilesMalformed(t *testing.T) {
+	for _, test := range malformedDistrictCSVs {
+		withCSVFile(t, test.contents, func(path string) {
+			before, _ := GetDistrictAt("CA", 1, inCycle(2014))
+
+			err := LoadDistrictFiles(path)
+			if err == nil {
+				t.Errorf("%s: Loaded without an error", test.name)
+			}
+
+			after, _ := GetDistrictAt("CA", 1, inCycle(2014))
+			if after != before {
+				t.Errorf("%s: Figures changed from %d to %d", test.name, before, after)
+			}
+		})
+	}
+}
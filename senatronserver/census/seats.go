@@ -0,0 +1,283 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// HouseSeats is the number of seats in the House of Representatives.
+const HouseSeats = 435
+
+// apportionments maps each census year to the number of House seats
+// each state was apportioned as a result of it.
+var apportionments = map[int]map[string]int{
+	// 1980 Census apportionment, effective 1983
+	1980: {
+		"AK": 1,
+		"AL": 7,
+		"AR": 4,
+		"AZ": 5,
+		"CA": 45,
+		"CO": 6,
+		"CT": 6,
+		"DE": 1,
+		"FL": 19,
+		"GA": 10,
+		"HI": 2,
+		"IA": 6,
+		"ID": 2,
+		"IL": 22,
+		"IN": 10,
+		"KS": 5,
+		"KY": 7,
+		"LA": 8,
+		"MA": 11,
+		"MD": 8,
+		"ME": 2,
+		"MI": 18,
+		"MN": 8,
+		"MO": 9,
+		"MS": 5,
+		"MT": 2,
+		"NC": 11,
+		"ND": 1,
+		"NE": 3,
+		"NH": 2,
+		"NJ": 14,
+		"NM": 3,
+		"NV": 2,
+		"NY": 34,
+		"OH": 21,
+		"OK": 6,
+		"OR": 5,
+		"PA": 23,
+		"RI": 2,
+		"SC": 6,
+		"SD": 1,
+		"TN": 9,
+		"TX": 27,
+		"UT": 3,
+		"VA": 10,
+		"VT": 1,
+		"WA": 8,
+		"WI": 9,
+		"WV": 4,
+		"WY": 1,
+	},
+	// 1990 Census apportionment, effective 1993
+	1990: {
+		"AK": 1,
+		"AL": 7,
+		"AR": 4,
+		"AZ": 6,
+		"CA": 52,
+		"CO": 6,
+		"CT": 6,
+		"DE": 1,
+		"FL": 23,
+		"GA": 11,
+		"HI": 2,
+		"IA": 5,
+		"ID": 2,
+		"IL": 20,
+		"IN": 10,
+		"KS": 4,
+		"KY": 6,
+		"LA": 7,
+		"MA": 10,
+		"MD": 8,
+		"ME": 2,
+		"MI": 16,
+		"MN": 8,
+		"MO": 9,
+		"MS": 5,
+		"MT": 1,
+		"NC": 12,
+		"ND": 1,
+		"NE": 3,
+		"NH": 2,
+		"NJ": 13,
+		"NM": 3,
+		"NV": 2,
+		"NY": 31,
+		"OH": 19,
+		"OK": 6,
+		"OR": 5,
+		"PA": 21,
+		"RI": 2,
+		"SC": 6,
+		"SD": 1,
+		"TN": 9,
+		"TX": 30,
+		"UT": 3,
+		"VA": 11,
+		"VT": 1,
+		"WA": 9,
+		"WI": 9,
+		"WV": 3,
+		"WY": 1,
+	},
+	// 2000 Census apportionment, effective 2003
+	2000: {
+		"AK": 1,
+		"AL": 7,
+		"AR": 4,
+		"AZ": 8,
+		"CA": 53,
+		"CO": 7,
+		"CT": 5,
+		"DE": 1,
+		"FL": 25,
+		"GA": 13,
+		"HI": 2,
+		"IA": 5,
+		"ID": 2,
+		"IL": 19,
+		"IN": 9,
+		"KS": 4,
+		"KY": 6,
+		"LA": 7,
+		"MA": 10,
+		"MD": 8,
+		"ME": 2,
+		"MI": 15,
+		"MN": 8,
+		"MO": 9,
+		"MS": 4,
+		"MT": 1,
+		"NC": 13,
+		"ND": 1,
+		"NE": 3,
+		"NH": 2,
+		"NJ": 13,
+		"NM": 3,
+		"NV": 3,
+		"NY": 29,
+		"OH": 18,
+		"OK": 5,
+		"OR": 5,
+		"PA": 19,
+		"RI": 2,
+		"SC": 6,
+		"SD": 1,
+		"TN": 9,
+		"TX": 32,
+		"UT": 3,
+		"VA": 11,
+		"VT": 1,
+		"WA": 9,
+		"WI": 8,
+		"WV": 3,
+		"WY": 1,
+	},
+	// 2010 Census apportionment, effective 2013
+	2010: {
+		"AK": 1,
+		"AL": 7,
+		"AR": 4,
+		"AZ": 9,
+		"CA": 53,
+		"CO": 7,
+		"CT": 5,
+		"DE": 1,
+		"FL": 27,
+		"GA": 14,
+		"HI": 2,
+		"IA": 4,
+		"ID": 2,
+		"IL": 18,
+		"IN": 9,
+		"KS": 4,
+		"KY": 6,
+		"LA": 6,
+		"MA": 9,
+		"MD": 8,
+		"ME": 2,
+		"MI": 14,
+		"MN": 8,
+		"MO": 8,
+		"MS": 4,
+		"MT": 1,
+		"NC": 13,
+		"ND": 1,
+		"NE": 3,
+		"NH": 2,
+		"NJ": 12,
+		"NM": 3,
+		"NV": 4,
+		"NY": 27,
+		"OH": 16,
+		"OK": 5,
+		"OR": 5,
+		"PA": 18,
+		"RI": 2,
+		"SC": 7,
+		"SD": 1,
+		"TN": 9,
+		"TX": 36,
+		"UT": 4,
+		"VA": 11,
+		"VT": 1,
+		"WA": 10,
+		"WI": 8,
+		"WV": 3,
+		"WY": 1,
+	},
+}
+
+// ErrNoSeats signals a seat lookup for a code that isn't a state, and
+// so has no House seats (DC and the territories only get non-voting
+// delegates).
+var ErrNoSeats = errors.New("No House seats for that state")
+
+// ApportionmentYear returns the census year whose apportionment was in
+// effect at the given time.  Each census's apportionment takes effect
+// with the congress that convenes on January 3rd three years later
+// (so the 2010 census applied from 2013); times before the earliest
+// one we have fall back to it.
+func ApportionmentYear(t time.Time) int {
+	years := make([]int, 0, len(apportionments))
+	for year := range apportionments {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	best := years[0]
+	for _, year := range years {
+		effective := time.Date(year+3, time.January, 3, 0, 0, 0, 0, time.UTC)
+		if !t.Before(effective) {
+			best = year
+		}
+	}
+	return best
+}
+
+// SeatsAt returns the number of House seats the given state had at the
+// given time, or ErrNoSeats if the code isn't a state.
+func SeatsAt(state string, t time.Time) (int, error) {
+	seats, ok := apportionments[ApportionmentYear(t)][state]
+	if !ok {
+		return 0, ErrNoSeats
+	}
+	return seats, nil
+}
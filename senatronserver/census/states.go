@@ -0,0 +1,158 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package census
+
+import (
+	"errors"
+	"sort"
+)
+
+// Jurisdiction describes what kind of place a state code refers to.
+type Jurisdiction string
+
+const (
+	// State is one of the fifty states, with two senators.
+	State Jurisdiction = "state"
+
+	// FederalDistrict is the District of Columbia, which has no
+	// senators.
+	FederalDistrict Jurisdiction = "federal-district"
+
+	// Territory is a U.S. territory like Puerto Rico, which has no
+	// senators.
+	Territory Jurisdiction = "territory"
+)
+
+// nonStates lists the jurisdiction of every code that isn't a state.
+var nonStates = map[string]Jurisdiction{
+	"DC": FederalDistrict,
+	"PR": Territory,
+}
+
+// JurisdictionOf returns what kind of jurisdiction the given state
+// code refers to, or an error if the code is invalid.
+func JurisdictionOf(code string) (Jurisdiction, error) {
+	if _, ok := stateNames[code]; !ok {
+		return "", errors.New("State not found")
+	}
+	if jurisdiction, ok := nonStates[code]; ok {
+		return jurisdiction, nil
+	}
+	return State, nil
+}
+
+// IsRepresented reports whether the given state code has senators.
+func IsRepresented(code string) bool {
+	jurisdiction, err := JurisdictionOf(code)
+	return err == nil && jurisdiction == State
+}
+
+// RepresentedStates returns the codes of every state with senators,
+// in alphabetical order.
+func RepresentedStates() []string {
+	out := make([]string, 0, len(stateNames)-len(nonStates))
+	for code := range stateNames {
+		if IsRepresented(code) {
+			out = append(out, code)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// UnrepresentedJurisdictions returns the codes of every jurisdiction
+// without senators, in alphabetical order.
+func UnrepresentedJurisdictions() []string {
+	out := make([]string, 0, len(nonStates))
+	for code := range nonStates {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// stateNames maps every state code we know about to its full name, as
+// it appears in Census Bureau tables.
+var stateNames = map[string]string{
+	"AK": "Alaska",
+	"AL": "Alabama",
+	"AR": "Arkansas",
+	"AZ": "Arizona",
+	"CA": "California",
+	"CO": "Colorado",
+	"CT": "Connecticut",
+	"DC": "District of Columbia",
+	"DE": "Delaware",
+	"FL": "Florida",
+	"GA": "Georgia",
+	"HI": "Hawaii",
+	"IA": "Iowa",
+	"ID": "Idaho",
+	"IL": "Illinois",
+	"IN": "Indiana",
+	"KS": "Kansas",
+	"KY": "Kentucky",
+	"LA": "Louisiana",
+	"MA": "Massachusetts",
+	"MD": "Maryland",
+	"ME": "Maine",
+	"MI": "Michigan",
+	"MN": "Minnesota",
+	"MO": "Missouri",
+	"MS": "Mississippi",
+	"MT": "Montana",
+	"NC": "North Carolina",
+	"ND": "North Dakota",
+	"NE": "Nebraska",
+	"NH": "New Hampshire",
+	"NJ": "New Jersey",
+	"NM": "New Mexico",
+	"NV": "Nevada",
+	"NY": "New York",
+	"OH": "Ohio",
+	"OK": "Oklahoma",
+	"OR": "Oregon",
+	"PA": "Pennsylvania",
+	"PR": "Puerto Rico",
+	"RI": "Rhode Island",
+	"SC": "South Carolina",
+	"SD": "South Dakota",
+	"TN": "Tennessee",
+	"TX": "Texas",
+	"UT": "Utah",
+	"VA": "Virginia",
+	"VT": "Vermont",
+	"WA": "Washington",
+	"WI": "Wisconsin",
+	"WV": "West Virginia",
+	"WY": "Wyoming",
+}
+
+// stateCodes maps full state names (and a few alternate spellings the
+// Census Bureau uses) back to state codes.
+var stateCodes = func() map[string]string {
+	out := map[string]string{
+		"Puerto Rico Commonwealth": "PR",
+	}
+	for code, name := range stateNames {
+		out[name] = code
+	}
+	return out
+}()
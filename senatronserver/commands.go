@@ -0,0 +1,215 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/analysis"
+	"github.com/senatron/senatron/senatronserver/context"
+	"github.com/senatron/senatron/senatronserver/senategov"
+	"github.com/senatron/senatron/senatronserver/store"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"log"
+)
+
+// runCommand runs one of the server's offline modes, as selected by
+// the positional command-line arguments, instead of starting up the
+// HTTP server.
+func runCommand(config *Config, args []string) error {
+	switch args[0] {
+	case "import-xml":
+		if len(args) != 2 {
+			return errors.New("Usage: import-xml <dir>")
+		}
+		return importXML(config, args[1])
+
+	case "divergent":
+		if len(args) != 1 {
+			return errors.New("Usage: divergent")
+		}
+		return divergent(config)
+
+	case "tally":
+		if len(args) != 2 {
+			return errors.New("Usage: tally <roll ID>")
+		}
+		return tally(config, args[1])
+
+	default:
+		return fmt.Errorf("Unknown command %q", args[0])
+	}
+}
+
+// importXML parses a directory of senate.gov XML files and saves the
+// resulting votes into the configured store.
+func importXML(config *Config, dir string) error {
+	if config.Store.Path == "" {
+		return errors.New("import-xml requires a store path")
+	}
+
+	votes, err := senategov.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	voteStore, err := store.Open(config.Store.Path)
+	if err != nil {
+		return err
+	}
+	defer voteStore.Close()
+
+	for _, vote := range votes {
+		// Don't let a vote menu summary clobber a complete vote
+		// that's already in the store.
+		if len(vote.Voters) == 0 {
+			existing, err := voteStore.GetVote(vote.RollID)
+			if err == nil && len(existing.Voters) > 0 {
+				continue
+			}
+		}
+
+		err = voteStore.PutVote(vote)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Imported %d votes from %s", len(votes), dir)
+	return nil
+}
+
+// divergent ranks every vote in the configured store by how far apart
+// its senate and popular outcomes were, and prints the results.
+func divergent(config *Config) error {
+	if config.Store.Path == "" {
+		return errors.New("divergent requires a store path")
+	}
+
+	voteStore, err := store.Open(config.Store.Path)
+	if err != nil {
+		return err
+	}
+	defer voteStore.Close()
+
+	divergences, err := analysis.FindDivergences(
+		voteStore,
+		sunlight.VoteQuery{},
+		analysis.Options{},
+		0,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, d := range divergences {
+		flag := " "
+		if d.CounterMajoritarian {
+			flag = "!"
+		}
+		fmt.Printf(
+			"%4d %s %-14s senate %6.2f%% yea, popular %6.2f%% yea "+
+				"(%+.2f)  %s\n",
+			i+1,
+			flag,
+			d.RollID,
+			d.SenateYea,
+			d.PopularYea,
+			d.Gap,
+			d.Question,
+		)
+	}
+	return nil
+}
+
+// tally fetches a single vote from the configured source and prints
+// its senate and popular tallies.
+func tally(config *Config, rollID string) error {
+	globalContext := &context.GlobalContext{}
+	err := initVoteSource(globalContext, config)
+	if err != nil {
+		return err
+	}
+
+	vote, err := globalContext.Votes.GetVote(rollID)
+	if err != nil {
+		return err
+	}
+	voteTally, err := analysis.NewTally(vote, analysis.Options{})
+	if err != nil {
+		return err
+	}
+
+	printTally(voteTally)
+	return nil
+}
+
+// printTally dumps a human-readable comparison of a tally to stdout.
+func printTally(tally analysis.Tally) {
+	fmt.Println("\n" + tally.RollID)
+	fmt.Println(tally.Question)
+	fmt.Printf(
+		"Weighted by %s scheme on %s population, split by %s policy\n",
+		tally.Scheme,
+		tally.Basis,
+		tally.Policy,
+	)
+	fmt.Printf("Requires %s: %s\n", tally.Threshold, tally.Summary())
+	fmt.Printf("Malapportionment: %.2f%%\n", tally.Malapportionment())
+	for _, k := range tally.Positions() {
+		fmt.Println(k)
+		fmt.Printf(
+			"    Senate: %d/%d (%.2f%%)\n",
+			tally.Senate[k],
+			tally.SenateTotal,
+			tally.SenatePercent(k),
+		)
+		fmt.Printf(
+			"    Popular: %d/%d (%.2f%%)\n",
+			int(tally.Popular[k]),
+			int(tally.PopularTotal),
+			tally.PopularPercent(k),
+		)
+	}
+	for _, party := range tally.PartyNames() {
+		fmt.Println(party)
+		for _, k := range tally.PartyPositions(party) {
+			fmt.Printf(
+				"    %s: %d senators (%.2f%%), %d people (%.2f%%)\n",
+				k,
+				tally.Parties[party].Senate[k],
+				tally.PartySenatePercent(party, k),
+				int(tally.Parties[party].Popular[k]),
+				tally.PartyPopularPercent(party, k),
+			)
+		}
+	}
+	fmt.Printf(
+		"Unrepresented: %d (%.2f%%)\n",
+		int(tally.Unrepresented),
+		tally.UnrepresentedPercent(),
+	)
+	if tally.Vacancies > 0 {
+		fmt.Printf("Vacant seats: %d\n", tally.Vacancies)
+	}
+	if len(tally.UnknownStates) > 0 {
+		fmt.Printf("Unknown states: %v\n", tally.UnknownStates)
+	}
+}
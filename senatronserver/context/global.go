@@ -21,6 +21,7 @@ package context
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/senatron/senatron/senatronserver/source"
 	"html/template"
 	"io"
 )
@@ -31,8 +32,11 @@ import (
 type GlobalContext struct {
 	Router    *mux.Router
 	Templates struct {
-		Index *template.Template
+		Index      *template.Template
+		Vote       *template.Template
+		Legislator *template.Template
+		Bill       *template.Template
 	}
-	SunlightAPIKey string
-	LogOut         io.Writer
+	Votes  source.VoteSource
+	LogOut io.Writer
 }
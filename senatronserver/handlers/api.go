@@ -0,0 +1,283 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"github.com/senatron/senatron/senatronserver/analysis"
+	"github.com/senatron/senatron/senatronserver/context"
+	"github.com/senatron/senatron/senatronserver/power"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"net/http"
+	"time"
+)
+
+// voteSummary returns the basic facts about a vote, without its
+// voters, for JSON output.
+func voteSummary(vote sunlight.Vote) map[string]interface{} {
+	return map[string]interface{}{
+		"roll_id":       vote.RollID,
+		"chamber":       vote.Chamber,
+		"congress":      vote.Congress,
+		"session":       vote.Session,
+		"voted_at":      vote.VotedAt,
+		"roll_type":     vote.RollType,
+		"question":      vote.Question,
+		"required":      vote.Required,
+		"result":        vote.Result,
+		"bill_id":       vote.BillID,
+		"nomination_id": vote.NominationID,
+	}
+}
+
+// APIVotes serves a page of votes as JSON.  Votes can be filtered
+// with the "congress", "session", "chamber", "since", "until",
+// "bill_id", "nomination_id", "result" and "q" (question text) query
+// parameters, and paged through with "page" and "per_page".
+func APIVotes(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := voteQuery(r)
+
+		votes, err := globalContext.Votes.ListVotes(query)
+		if err != nil {
+			panic(err)
+		}
+
+		summaries := make([]map[string]interface{}, len(votes))
+		for i, vote := range votes {
+			summaries[i] = voteSummary(vote)
+		}
+
+		page, perPage := query.Pagination()
+		writeJSON(w, map[string]interface{}{
+			"page":     page,
+			"per_page": perPage,
+			"votes":    summaries,
+		})
+	}
+}
+
+// maxDivergentScan caps the number of votes APIDivergent will examine
+// in a single request, when the vote source has them at hand.
+const maxDivergentScan = 1000
+
+// APIDivergent serves votes ranked by how far apart their senate and
+// popular outcomes were, counter-majoritarian votes first, as JSON.
+// It takes the same filters and pagination parameters as APIVotes,
+// and the same tally parameters as APIVote.  Only the most recent
+// votes matching the filters are examined.
+func APIDivergent(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := voteQuery(r)
+
+		divergences, err := analysis.FindDivergences(
+			globalContext.Votes,
+			query,
+			tallyOptions(r),
+			scanLimit(globalContext, maxDivergentScan),
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		page, perPage := query.Pagination()
+		start := (page - 1) * perPage
+		if start > len(divergences) {
+			start = len(divergences)
+		}
+		end := start + perPage
+		if end > len(divergences) {
+			end = len(divergences)
+		}
+
+		writeJSON(w, map[string]interface{}{
+			"page":     page,
+			"per_page": perPage,
+			"total":    len(divergences),
+			"votes":    divergences[start:end],
+		})
+	}
+}
+
+// APIVote serves the senate and popular tallies for a single roll
+// call as JSON.  The "basis" query parameter selects the population
+// figures to weight the popular vote by, and "policy" selects how
+// they're split between senators.
+func APIVote(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vote := fetchVote(globalContext, r)
+
+		tally := tallyVote(r, vote)
+		bill, nomination := voteSubject(globalContext, r, vote)
+		out := voteSummary(vote)
+		out["bill"] = bill
+		out["nomination"] = nomination
+		for k, v := range tallyOutcome(tally) {
+			out[k] = v
+		}
+		out["malapportionment"] = tally.Malapportionment()
+		writeJSON(w, out)
+	}
+}
+
+// tallyOutcome returns a tally along with its outcomes, for JSON
+// output.
+func tallyOutcome(tally analysis.Tally) map[string]interface{} {
+	return map[string]interface{}{
+		"tally":          tally,
+		"senate_passed":  tally.SenatePassed(),
+		"popular_passed": tally.PopularPassed(),
+		"summary":        tally.Summary(),
+	}
+}
+
+// APIVoteCounterfactual serves the tallies of a single vote both as it
+// happened and with the changes described by the
+// analysis.Counterfactual in the request body, so you can see what
+// would have happened had some senators voted the other way.  It takes
+// the same tally parameters as APIVote.
+func APIVoteCounterfactual(
+	globalContext *context.GlobalContext,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vote := fetchVote(globalContext, r)
+
+		var counterfactual analysis.Counterfactual
+		readJSON(w, r, &counterfactual)
+
+		changed, err := counterfactual.Apply(vote)
+		if err == analysis.ErrUnknownVoter ||
+			err == analysis.ErrUnknownState ||
+			err == analysis.ErrDelegationSize ||
+			err == analysis.ErrUnknownDistrict ||
+			err == analysis.ErrInvalidPosition {
+			panic(Err400)
+		} else if err != nil {
+			panic(err)
+		}
+
+		writeJSON(w, map[string]interface{}{
+			"roll_id":        vote.RollID,
+			"actual":         tallyOutcome(tallyVote(r, vote)),
+			"counterfactual": tallyOutcome(tallyVote(r, changed)),
+		})
+	}
+}
+
+// APIVoteStates serves the per-state breakdown of a single vote,
+// sorted by the sort query parameter.
+func APIVoteStates(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vote := fetchVote(globalContext, r)
+		tally := tallyVote(r, vote)
+
+		writeJSON(w, map[string]interface{}{
+			"roll_id": vote.RollID,
+			"basis":   tally.Basis,
+			"policy":  tally.Policy,
+			"scheme":  tally.Scheme,
+			"states":  sortedStates(r, tally),
+		})
+	}
+}
+
+// APIVoteSchemes serves the popular outcome of a single vote under
+// every weighting scheme side by side.  It takes the same basis and
+// policy parameters as APIVote.
+func APIVoteSchemes(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vote := fetchVote(globalContext, r)
+
+		schemes, err := analysis.CompareSchemes(vote, tallyOptions(r))
+		if err != nil {
+			panic(err)
+		}
+
+		writeJSON(w, map[string]interface{}{
+			"roll_id":  vote.RollID,
+			"question": vote.Question,
+			"required": vote.Required,
+			"schemes":  schemes,
+		})
+	}
+}
+
+// APILegislator serves a legislator's details along with how often
+// they voted with the population-weighted majority.  It takes the same
+// filters as APIVotes (bar chamber, which is always the senate) and
+// the same tally parameters as APIVote.  Only the most recent votes
+// matching the filters are examined.
+func APILegislator(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profile := profileLegislator(globalContext, r)
+
+		writeJSON(w, map[string]interface{}{
+			"profile":          profile,
+			"majority_percent": profile.MajorityPercent(),
+		})
+	}
+}
+
+// APIBillComparison serves the passage votes on a bill in each chamber
+// side by side, along with the bill's details if the source has them.
+// It takes the same tally parameters as APIVote.
+func APIBillComparison(
+	globalContext *context.GlobalContext,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		comparison := compareBill(globalContext, r)
+		bill, _ := voteSubject(
+			globalContext,
+			r,
+			sunlight.Vote{BillID: comparison.BillID},
+		)
+
+		writeJSON(w, map[string]interface{}{
+			"bill":       bill,
+			"comparison": comparison,
+		})
+	}
+}
+
+// APIPower serves the Banzhaf and Shapley-Shubik voting power indices
+// of every state, both in the senate as it is and in a senate where
+// each state's votes were weighted by its population.  It takes a
+// "basis" parameter like APIVote, and a "date" parameter to pick which
+// census figures to use (the most recent by default).
+func APIPower(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		basis := basisParam(r)
+		date := timeParam(r, "date", 0)
+		if date.IsZero() {
+			date = time.Now()
+		}
+
+		population, err := power.PopulationIndices(basis, date)
+		if err != nil {
+			panic(err)
+		}
+
+		writeJSON(w, map[string]interface{}{
+			"basis":      basis,
+			"date":       date,
+			"senate":     power.SenateIndices(),
+			"population": population,
+		})
+	}
+}
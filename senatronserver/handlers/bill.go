@@ -0,0 +1,52 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"github.com/senatron/senatron/senatronserver/context"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"net/http"
+)
+
+// Bill renders the page comparing a bill's passage votes in each
+// chamber.  Like APIBillComparison, it takes "basis" and "policy" query
+// parameters.
+func Bill(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		comparison := compareBill(globalContext, r)
+		bill, _ := voteSubject(
+			globalContext,
+			r,
+			sunlight.Vote{BillID: comparison.BillID},
+		)
+
+		err := globalContext.Templates.Bill.Execute(
+			w,
+			map[string]interface{}{
+				"BillID":     comparison.BillID,
+				"Bill":       bill,
+				"Comparison": comparison,
+			},
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -24,12 +24,21 @@ import (
 	"net/http"
 )
 
+// Err400 triggers a 400 response when thrown in a panic.
+var Err400 = errors.New("Bad request")
+
 // Err404 triggers a 404 response when thrown in a panic.
 var Err404 = errors.New("Not found")
 
 // Err500 triggers a 500 response when thrown in a panic.
 var Err500 = errors.New("Internal error")
 
+// FourHundred writes out a standard bad request error message.
+func FourHundred(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte("400 - Bad Request"))
+}
+
 // FourOhFour writes out a standard page not found error message.
 func FourOhFour(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotFound)
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSON encodes data as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(data)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// maxJSONBody caps the size of the JSON request bodies readJSON will
+// read.
+const maxJSONBody = 1 << 20
+
+// readJSON decodes the JSON body of the request into data, panicking
+// with Err400 if it's malformed or too big.
+func readJSON(w http.ResponseWriter, r *http.Request, data interface{}) {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxJSONBody))
+	err := decoder.Decode(data)
+	if err != nil {
+		panic(Err400)
+	}
+}
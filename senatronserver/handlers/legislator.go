@@ -0,0 +1,45 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"github.com/senatron/senatron/senatronserver/context"
+	"net/http"
+)
+
+// Legislator renders the profile page for a single legislator.  Like
+// APILegislator, it takes vote filters and "basis" and "policy" query
+// parameters.
+func Legislator(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profile := profileLegislator(globalContext, r)
+
+		err := globalContext.Templates.Legislator.Execute(
+			w,
+			map[string]interface{}{
+				"Legislator": profile.Legislator,
+				"Profile":    profile,
+			},
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/senatron/senatron/senatronserver/analysis"
+	"github.com/senatron/senatron/senatronserver/census"
+	"github.com/senatron/senatron/senatronserver/context"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxPerPage caps the page size clients can ask for in listings.
+const maxPerPage = 50
+
+// fetchVote looks up the vote named by the rollID route variable,
+// panicking with Err404 if there's no such vote.  Votes the source has
+// but can't make sense of (like the House's speaker elections, which
+// are voted by name) are logged and treated as missing too, since
+// there's nothing to show for them.
+func fetchVote(
+	globalContext *context.GlobalContext,
+	r *http.Request,
+) sunlight.Vote {
+	vote, err := globalContext.Votes.GetVote(mux.Vars(r)["rollID"])
+	if malformed, ok := err.(*sunlight.MalformedVoteError); ok {
+		context.Get(r).Logger.Printf("Can't show vote: %v", malformed)
+		panic(Err404)
+	} else if err == sunlight.ErrVoteNotFound {
+		panic(Err404)
+	} else if err != nil {
+		panic(err)
+	}
+	return vote
+}
+
+// voteSubject looks up the bill or nomination a vote was taken on,
+// returning nil for whichever it wasn't, or if the source doesn't know
+// about it.  Failed lookups are logged rather than failing the
+// request, since the vote is still worth showing without them.
+func voteSubject(
+	globalContext *context.GlobalContext,
+	r *http.Request,
+	vote sunlight.Vote,
+) (bill *sunlight.Bill, nomination *sunlight.Nomination) {
+	logger := context.Get(r).Logger
+
+	if vote.BillID != "" {
+		b, err := globalContext.Votes.GetBill(vote.BillID)
+		if err == nil {
+			bill = &b
+		} else if err != sunlight.ErrBillNotFound {
+			logger.Printf("Failed to look up bill %s: %v", vote.BillID, err)
+		}
+	}
+
+	if vote.NominationID != "" {
+		n, err := globalContext.Votes.GetNomination(vote.NominationID)
+		if err == nil {
+			nomination = &n
+		} else if err != sunlight.ErrNominationNotFound {
+			logger.Printf(
+				"Failed to look up nomination %s: %v",
+				vote.NominationID,
+				err,
+			)
+		}
+	}
+	return
+}
+
+// compareBill compares the passage votes in each chamber on the bill
+// named by the billID route variable, weighted with the request's
+// tally options, panicking with Err404 if neither chamber has one.
+func compareBill(
+	globalContext *context.GlobalContext,
+	r *http.Request,
+) analysis.BillComparison {
+	comparison, err := analysis.NewBillComparison(
+		globalContext.Votes,
+		mux.Vars(r)["billID"],
+		tallyOptions(r),
+	)
+	if err == analysis.ErrNoPassageVotes {
+		panic(Err404)
+	} else if err != nil {
+		panic(err)
+	}
+	return comparison
+}
+
+// maxProfileScan caps the number of votes examined when profiling a
+// legislator, most recent first, when the vote source has them at
+// hand.
+const maxProfileScan = 1000
+
+// maxRemoteScan caps the number of votes examined in a single request
+// when the vote source has to fetch them, since each one listed
+// without its voters costs another request upstream.
+const maxRemoteScan = 100
+
+// scanLimit returns limit if the global vote source has its votes at
+// hand (that is, it's a source.VoteIterator), and otherwise whichever
+// is lower of limit and maxRemoteScan.
+func scanLimit(globalContext *context.GlobalContext, limit int) int {
+	if _, ok := globalContext.Votes.(source.VoteIterator); ok {
+		return limit
+	}
+	if limit > maxRemoteScan {
+		return maxRemoteScan
+	}
+	return limit
+}
+
+// fetchLegislator looks up the legislator named by the bioguideID
+// route variable, panicking with Err404 if there's no such legislator.
+func fetchLegislator(
+	globalContext *context.GlobalContext,
+	r *http.Request,
+) sunlight.Legislator {
+	legislator, err := globalContext.Votes.GetLegislator(
+		mux.Vars(r)["bioguideID"],
+	)
+	if err == sunlight.ErrLegislatorNotFound {
+		panic(Err404)
+	} else if err != nil {
+		panic(err)
+	}
+	return legislator
+}
+
+// profileLegislator profiles the legislator named by the bioguideID
+// route variable over the senate votes matching the request's vote
+// filters, weighted with its tally options.
+func profileLegislator(
+	globalContext *context.GlobalContext,
+	r *http.Request,
+) analysis.Profile {
+	legislator := fetchLegislator(globalContext, r)
+
+	query := voteQuery(r)
+	query.Chamber = sunlight.Senate
+
+	profile, err := analysis.NewProfile(
+		globalContext.Votes,
+		legislator,
+		query,
+		tallyOptions(r),
+		scanLimit(globalContext, maxProfileScan),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return profile
+}
+
+// tallyOptions reads analysis options from the request's query
+// parameters, panicking with Err400 if any of them are invalid.
+func tallyOptions(r *http.Request) analysis.Options {
+	query := r.URL.Query()
+	basis := basisParam(r)
+
+	policy, err := analysis.ParsePolicy(query.Get("policy"))
+	if err != nil {
+		panic(Err400)
+	}
+
+	scheme, err := analysis.ParseScheme(query.Get("scheme"))
+	if err != nil {
+		panic(Err400)
+	}
+
+	return analysis.Options{Basis: basis, Policy: policy, Scheme: scheme}
+}
+
+// basisParam reads the population basis from the request's query
+// parameters, panicking with Err400 if it's invalid or has no figures
+// loaded.
+func basisParam(r *http.Request) census.Basis {
+	basis, err := census.ParseBasis(r.URL.Query().Get("basis"))
+	if err != nil || !census.Available(basis) {
+		panic(Err400)
+	}
+	return basis
+}
+
+// tallyVote counts up a vote with the options from the request's query
+// parameters.
+func tallyVote(r *http.Request, vote sunlight.Vote) analysis.Tally {
+	tally, err := analysis.NewTally(vote, tallyOptions(r))
+	if err != nil {
+		panic(err)
+	}
+	return tally
+}
+
+// sortedStates returns the tally's per-state breakdown sorted by the
+// request's sort query parameter, panicking with Err400 if that isn't
+// a key analysis.SortStates knows.
+func sortedStates(r *http.Request, tally analysis.Tally) []analysis.StateTally {
+	err := analysis.SortStates(tally.States, r.URL.Query().Get("sort"))
+	if err != nil {
+		panic(Err400)
+	}
+	return tally.States
+}
+
+// voteQuery reads vote listing filters from the request's query
+// parameters, panicking with Err400 if any of them are invalid.  A
+// session is only meaningful within a congress, so one can't be given
+// without the other.  Dates can be given either as full RFC 3339
+// timestamps or as plain dates, in which case "until" includes the
+// whole day.
+func voteQuery(r *http.Request) sunlight.VoteQuery {
+	query := r.URL.Query()
+	out := sunlight.VoteQuery{
+		Chamber:      query.Get("chamber"),
+		BillID:       query.Get("bill_id"),
+		NominationID: query.Get("nomination_id"),
+		Result:       query.Get("result"),
+		Question:     query.Get("q"),
+	}
+
+	if out.Chamber != "" &&
+		out.Chamber != sunlight.Senate &&
+		out.Chamber != sunlight.House {
+		panic(Err400)
+	}
+
+	out.Congress = intParam(r, "congress")
+	out.Session = intParam(r, "session")
+	if out.Session != 0 && out.Congress == 0 {
+		panic(Err400)
+	}
+	out.Page = intParam(r, "page")
+	out.PerPage = intParam(r, "per_page")
+	if out.PerPage > maxPerPage {
+		out.PerPage = maxPerPage
+	}
+
+	out.Since = timeParam(r, "since", 0)
+	out.Until = timeParam(r, "until", 24*time.Hour-time.Nanosecond)
+
+	return out
+}
+
+// intParam reads a non-negative integer query parameter, returning 0
+// if it's missing and panicking with Err400 if it's malformed.
+func intParam(r *http.Request, name string) int {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		panic(Err400)
+	}
+	return value
+}
+
+// timeParam reads a timestamp or date query parameter, returning the
+// zero time if it's missing and panicking with Err400 if it's
+// malformed.  Plain dates have dayOffset added to them.
+func timeParam(
+	r *http.Request,
+	name string,
+	dayOffset time.Duration,
+) time.Time {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		panic(Err400)
+	}
+	return t.Add(dayOffset)
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"github.com/senatron/senatron/senatronserver/analysis"
+	"github.com/senatron/senatron/senatronserver/context"
+	"net/http"
+)
+
+// Vote renders the comparison page for a single roll call.  Like
+// APIVote, it takes "basis" and "policy" query parameters, as well as
+// a "sort" parameter for the per-state table.
+func Vote(globalContext *context.GlobalContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vote := fetchVote(globalContext, r)
+		tally := tallyVote(r, vote)
+		bill, nomination := voteSubject(globalContext, r, vote)
+
+		// Each column of the state table links back to this page
+		// sorted by that column, keeping the other options intact.
+		sortLinks := map[string]string{}
+		for _, key := range analysis.StateSortKeys {
+			query := r.URL.Query()
+			query.Set("sort", key)
+			sortLinks[key] = "?" + query.Encode()
+		}
+
+		err := globalContext.Templates.Vote.Execute(
+			w,
+			map[string]interface{}{
+				"Vote":       vote,
+				"Bill":       bill,
+				"Nomination": nomination,
+				"Tally":      tally,
+				"States":     sortedStates(r, tally),
+				"SortLinks":  sortLinks,
+			},
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
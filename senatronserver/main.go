@@ -24,12 +24,15 @@ import (
 	"github.com/bieber/conflag"
 	"github.com/senatron/senatron/senatronserver/census"
 	"github.com/senatron/senatron/senatronserver/context"
-	"github.com/senatron/senatron/senatronserver/sunlight"
+	"github.com/senatron/senatron/senatronserver/power"
 	"golang.org/x/crypto/ssh/terminal"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 )
 
 // Config defines configuration options for the server.
@@ -42,14 +45,33 @@ type Config struct {
 	Log struct {
 		FilePath string
 	}
+	Source struct {
+		Provider string
+	}
 	Sunlight struct {
 		APIKey string
 	}
+	ProPublica struct {
+		APIKey string
+	}
+	SenateGov struct {
+		Directory string
+	}
+	Store struct {
+		Path string
+	}
+	Census struct {
+		FilePath                 string
+		VotingAgeFilePath        string
+		CitizenVotingAgeFilePath string
+		RegisteredFilePath       string
+		DistrictFilePath         string
+	}
 }
 
 func main() {
 	config, parser := getConfig()
-	_, err := parser.Read()
+	args, err := parser.Read()
 	if err != nil || config.Help {
 		exitCode := 0
 
@@ -75,51 +97,38 @@ func main() {
 		logOut = fout
 	}
 
-	// TODO: Remove ...
-	vote, err := sunlight.GetVote(config.Sunlight.APIKey, "s396-2009")
+	censusFiles := map[census.Basis]string{
+		census.Total:            config.Census.FilePath,
+		census.VotingAge:        config.Census.VotingAgeFilePath,
+		census.CitizenVotingAge: config.Census.CitizenVotingAgeFilePath,
+		census.Registered:       config.Census.RegisteredFilePath,
+	}
+	districtFiles := config.Census.DistrictFilePath
+	err = loadCensusFiles(censusFiles, districtFiles)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go reloadCensusOnHangup(censusFiles, districtFiles)
 
-	senateVotes := map[string]float64{}
-	popularVotes := map[string]float64{}
-	for _, v := range vote.Voters {
-		population, _ := census.Get(v.Info.State)
-		if existing, ok := senateVotes[v.Vote]; ok {
-			senateVotes[v.Vote] = existing + 1
-			popularVotes[v.Vote] += float64(population) / 2
-		} else {
-			senateVotes[v.Vote] = 1
-			popularVotes[v.Vote] = float64(population) / 2
+	if len(args) > 0 {
+		err = runCommand(config, args)
+		if err != nil {
+			log.Fatal(err)
 		}
+		return
 	}
 
-	senateTotal := float64(0)
-	popularTotal := float64(0)
-	for k, v := range senateVotes {
-		senateTotal += v
-		popularTotal += popularVotes[k]
+	if config.HTTP.StaticResourcesPath == "" {
+		log.Fatal("A static resources path is required to run the server")
 	}
 
-	fmt.Println("\n" + vote.RollID)
-	fmt.Println(vote.Question)
-	for k := range senateVotes {
-		fmt.Println(k)
-		fmt.Printf(
-			"    Senate: %d/%d (%.2f%%)\n",
-			int(senateVotes[k]),
-			int(senateTotal),
-			senateVotes[k]/senateTotal*100,
-		)
-		fmt.Printf(
-			"    Popular: %d/%d (%.2f%%)\n",
-			int(popularVotes[k]),
-			int(popularTotal),
-			popularVotes[k]/popularTotal*100,
-		)
+	globalContext := &context.GlobalContext{
+		LogOut: logOut,
 	}
-	// ...up to here
 
-	globalContext := &context.GlobalContext{
-		SunlightAPIKey: config.Sunlight.APIKey,
-		LogOut:         logOut,
+	err = initVoteSource(globalContext, config)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	initRoutes(globalContext, config.HTTP.StaticResourcesPath)
@@ -135,9 +144,56 @@ func main() {
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", config.HTTP.Port), nil))
 }
 
+// loadCensusFiles loads census figures for each basis from the
+// corresponding list of files (separated as in $PATH), skipping any
+// basis without one, and congressional district figures from the list
+// of district files, if there is one.
+func loadCensusFiles(
+	paths map[census.Basis]string,
+	districtPaths string,
+) error {
+	for basis, path := range paths {
+		if path == "" {
+			continue
+		}
+		err := census.LoadFiles(basis, filepath.SplitList(path)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if districtPaths == "" {
+		return nil
+	}
+	return census.LoadDistrictFiles(filepath.SplitList(districtPaths)...)
+}
+
+// reloadCensusOnHangup reloads census figures from the given files
+// every time the process receives a SIGHUP, and drops any voting power
+// indices worked out from the old ones.  Even a failed reload may have
+// replaced some bases' figures, so they're dropped either way.
+func reloadCensusOnHangup(
+	paths map[census.Basis]string,
+	districtPaths string,
+) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	for range hangups {
+		err := loadCensusFiles(paths, districtPaths)
+		power.ForgetPopulationIndices()
+		if err != nil {
+			log.Printf("Failed to reload census data: %v", err)
+		} else {
+			log.Printf("Reloaded census data")
+		}
+	}
+}
+
 func getConfig() (*Config, *conflag.Config) {
 	config := &Config{}
 	config.HTTP.Port = 8080
+	config.Source.Provider = "sunlight"
 
 	parser, err := conflag.New(config)
 	if err != nil {
@@ -145,7 +201,15 @@ func getConfig() (*Config, *conflag.Config) {
 	}
 
 	parser.ProgramName("senatronserver")
-	parser.ProgramDescription("HTTP server for senatron")
+	parser.ProgramDescription(
+		"HTTP server for senatron.  Run as " +
+			"\"senatronserver import-xml <dir>\" to import " +
+			"senate.gov XML files into the store, " +
+			"\"senatronserver divergent\" to rank the votes in " +
+			"the store by how far apart their senate and popular " +
+			"outcomes were, or \"senatronserver tally <roll ID>\" " +
+			"to print the comparison for a single vote.",
+	)
 	parser.ConfigFileLongFlag("config")
 
 	parser.Field("Help").
@@ -159,20 +223,82 @@ func getConfig() (*Config, *conflag.Config) {
 	parser.Field("HTTP.StaticResourcesPath").
 		ShortFlag('s').
 		LongFlag("static-resources").
-		Required().
-		Description("Root directory to load static resources from.")
+		Description(
+			"Root directory to load static resources from.  Required " +
+				"to run the HTTP server.",
+		)
 
 	parser.Field("Log.FilePath").
 		ShortFlag('l').
 		LongFlag("log-file").
 		Description("Optional log output file (logs go to stderr by default)")
 
+	parser.Field("Source.Provider").
+		LongFlag("source").
+		FileKey("provider").
+		Description(
+			"Where to get votes from: sunlight, propublica, " +
+				"senategov or store.",
+		)
+
 	parser.Field("Sunlight.APIKey").
 		ShortFlag('a').
 		LongFlag("api-key").
 		FileKey("api_key").
-		Required().
 		Description("Sunlight Foundation API key.")
 
+	parser.Field("ProPublica.APIKey").
+		LongFlag("propublica-api-key").
+		FileKey("api_key").
+		Description("ProPublica Congress API key.")
+
+	parser.Field("SenateGov.Directory").
+		LongFlag("senategov-dir").
+		FileKey("directory").
+		Description("Directory of senate.gov roll call XML files.")
+
+	parser.Field("Census.FilePath").
+		LongFlag("census-file").
+		FileKey("file_path").
+		Description(
+			"Optional Census Bureau CSV files to load state " +
+				"populations from, separated by colons, with " +
+				"later files taking precedence.  Send SIGHUP to " +
+				"reload them.",
+		)
+
+	parser.Field("Census.VotingAgeFilePath").
+		LongFlag("voting-age-file").
+		FileKey("voting_age_file_path").
+		Description("Optional CSV file of voting-age populations.")
+
+	parser.Field("Census.CitizenVotingAgeFilePath").
+		LongFlag("citizen-voting-age-file").
+		FileKey("citizen_voting_age_file_path").
+		Description(
+			"Optional CSV file of citizen voting-age populations.",
+		)
+
+	parser.Field("Census.RegisteredFilePath").
+		LongFlag("registered-file").
+		FileKey("registered_file_path").
+		Description("Optional CSV file of registered voter counts.")
+
+	parser.Field("Census.DistrictFilePath").
+		LongFlag("district-file").
+		FileKey("district_file_path").
+		Description(
+			"Optional CSV files of congressional district " +
+				"populations, separated by colons.",
+		)
+
+	parser.Field("Store.Path").
+		LongFlag("store").
+		FileKey("path").
+		Description(
+			"Optional file to cache votes in.  When set, votes are " +
+				"served from here before going to the source.",
+		)
+
 	return config, parser
 }
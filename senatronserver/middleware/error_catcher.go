@@ -38,6 +38,8 @@ func ErrorCatcher(next http.Handler) http.Handler {
 			}
 
 			switch err {
+			case handlers.Err400:
+				handlers.FourHundred(w, r)
 			case handlers.Err404:
 				handlers.FourOhFour(w, r)
 			default:
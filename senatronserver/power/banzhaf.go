@@ -0,0 +1,116 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"math/rand"
+)
+
+// Banzhaf returns each state's normalized Banzhaf index: the share of
+// all the swings in the game, where a swing is a winning coalition
+// that would lose without the state.  It's computed exactly from the
+// game's generating function when that's small enough, and otherwise
+// estimated by sampling coalitions, in which case exact is false.
+func (g Game) Banzhaf() (index []float64, exact bool) {
+	if len(g.Weights) <= maxExactPlayers &&
+		len(g.Weights)*g.Quota <= maxExactCells {
+		return g.exactBanzhaf(), true
+	}
+	return g.sampledBanzhaf(), false
+}
+
+// exactBanzhaf counts every state's swings using the generating
+// function of coalition weights, the product over every state of
+// (1 + x^weight), truncated below the quota.  Dividing a state's term
+// back out leaves the coalitions of everyone else, and the state
+// swings those weighing at least quota - weight but less than quota.
+// Counts are kept in uint64, whose wrapping arithmetic makes the
+// division exact so long as the true counts fit.
+func (g Game) exactBanzhaf() []float64 {
+	swings := make([]float64, len(g.Weights))
+	if g.Quota <= 0 {
+		return swings
+	}
+
+	all := make([]uint64, g.Quota)
+	all[0] = 1
+	for _, weight := range g.Weights {
+		// States without weight can never swing anything, and
+		// leaving them out of the product only scales everyone
+		// else's counts by the same factor.
+		if weight <= 0 {
+			continue
+		}
+		for w := g.Quota - 1; w >= weight; w-- {
+			all[w] += all[w-weight]
+		}
+	}
+
+	others := make([]uint64, g.Quota)
+	for i, weight := range g.Weights {
+		if weight <= 0 {
+			continue
+		}
+		for w := range others {
+			others[w] = all[w]
+			if w >= weight {
+				others[w] -= others[w-weight]
+			}
+		}
+
+		low := g.Quota - weight
+		if low < 0 {
+			low = 0
+		}
+		for w := low; w < g.Quota; w++ {
+			swings[i] += float64(others[w])
+		}
+	}
+	return normalize(swings)
+}
+
+// sampledBanzhaf estimates the Banzhaf index by drawing random
+// coalitions and counting who swings each one.
+func (g Game) sampledBanzhaf() []float64 {
+	random := rand.New(rand.NewSource(monteCarloSeed))
+	swings := make([]float64, len(g.Weights))
+	members := make([]bool, len(g.Weights))
+
+	for sample := 0; sample < monteCarloSamples; sample++ {
+		total := 0
+		for i, weight := range g.Weights {
+			members[i] = random.Int63()&1 == 1
+			if members[i] {
+				total += weight
+			}
+		}
+
+		for i, weight := range g.Weights {
+			if members[i] && total >= g.Quota && total-weight < g.Quota {
+				swings[i]++
+			} else if !members[i] &&
+				total < g.Quota &&
+				total+weight >= g.Quota {
+				swings[i]++
+			}
+		}
+	}
+	return normalize(swings)
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package power
+
+import (
+	"testing"
+)
+
+func TestBanzhaf(t *testing.T) {
+	for _, test := range knownGames {
+		index, exact := test.game.Banzhaf()
+		if !exact {
+			t.Errorf("%s: Wasn't computed exactly", test.name)
+		}
+		checkIndex(t, test.name, index, test.banzhaf, 1e-9)
+	}
+}
+
+// TestBanzhafBruteForce checks the generating function against
+// counting every swing in every coalition of a game.
+func TestBanzhafBruteForce(t *testing.T) {
+	game := Game{Weights: midSizeWeights[:12], Quota: 80}
+
+	swings := make([]float64, len(game.Weights))
+	for coalition := 0; coalition < 1<<uint(len(game.Weights)); coalition++ {
+		weight := 0
+		for i, w := range game.Weights {
+			if coalition&(1<<uint(i)) != 0 {
+				weight += w
+			}
+		}
+		for i, w := range game.Weights {
+			if coalition&(1<<uint(i)) != 0 &&
+				weight >= game.Quota && weight-w < game.Quota {
+				swings[i]++
+			}
+		}
+	}
+
+	index, _ := game.Banzhaf()
+	checkIndex(t, "brute force", index, normalize(swings), 1e-9)
+}
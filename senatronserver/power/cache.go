@@ -0,0 +1,98 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"github.com/senatron/senatron/senatronserver/census"
+	"sync"
+	"time"
+)
+
+// senateIndices holds SenateGame's indices, which never change, once
+// they've been computed.
+var senateIndices struct {
+	once    sync.Once
+	indices Indices
+}
+
+// SenateIndices returns the indices of SenateGame, computing them the
+// first time it's called.  The indices are shared between callers, so
+// they mustn't be modified.
+func SenateIndices() Indices {
+	senateIndices.once.Do(func() {
+		senateIndices.indices = SenateGame().Indices()
+	})
+	return senateIndices.indices
+}
+
+// populationKey identifies the census figures a PopulationGame was
+// built from.
+type populationKey struct {
+	basis census.Basis
+	year  int
+}
+
+// maxPopulationIndices caps the number of games PopulationIndices
+// holds on to the indices of.  Past that they're all dropped, which
+// only costs recomputing the handful of years people actually ask for.
+const maxPopulationIndices = 100
+
+// populationIndicesMutex guards populationIndices.
+var populationIndicesMutex = sync.Mutex{}
+var populationIndices = map[populationKey]Indices{}
+
+// PopulationIndices returns the indices of the PopulationGame for the
+// given basis at the given time.  Since the game only depends on the
+// basis and the year, its indices are computed once for each and held
+// on to until ForgetPopulationIndices is called.  The indices are
+// shared between callers, so they mustn't be modified.
+func PopulationIndices(basis census.Basis, at time.Time) (Indices, error) {
+	key := populationKey{basis: basis, year: at.Year()}
+
+	populationIndicesMutex.Lock()
+	indices, ok := populationIndices[key]
+	populationIndicesMutex.Unlock()
+	if ok {
+		return indices, nil
+	}
+
+	game, err := PopulationGame(basis, at)
+	if err != nil {
+		return Indices{}, err
+	}
+	indices = game.Indices()
+
+	populationIndicesMutex.Lock()
+	defer populationIndicesMutex.Unlock()
+	if len(populationIndices) >= maxPopulationIndices {
+		populationIndices = map[populationKey]Indices{}
+	}
+	populationIndices[key] = indices
+	return indices, nil
+}
+
+// ForgetPopulationIndices drops every index PopulationIndices has held
+// on to, so that they're worked out again from newly loaded census
+// figures.
+func ForgetPopulationIndices() {
+	populationIndicesMutex.Lock()
+	defer populationIndicesMutex.Unlock()
+	populationIndices = map[populationKey]Indices{}
+}
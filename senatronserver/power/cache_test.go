@@ -0,0 +1,68 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"github.com/senatron/senatron/senatronserver/census"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSenateIndices(t *testing.T) {
+	if !reflect.DeepEqual(SenateIndices(), SenateGame().Indices()) {
+		t.Error("SenateIndices doesn't match SenateGame's indices")
+	}
+}
+
+func TestPopulationIndices(t *testing.T) {
+	ForgetPopulationIndices()
+	defer ForgetPopulationIndices()
+
+	spring := time.Date(2010, 4, 1, 0, 0, 0, 0, time.UTC)
+	autumn := time.Date(2010, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	game, err := PopulationGame(census.Total, spring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := game.Indices()
+
+	for _, at := range []time.Time{spring, autumn} {
+		indices, err := PopulationIndices(census.Total, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(indices, expected) {
+			t.Errorf("Wrong indices for %v", at)
+		}
+	}
+	if len(populationIndices) != 1 {
+		t.Errorf(
+			"Holding %d sets of indices, expected one for the whole year",
+			len(populationIndices),
+		)
+	}
+
+	_, err = PopulationIndices(census.Registered, spring)
+	if err != census.ErrBasisUnavailable {
+		t.Errorf("Got %v for an unloaded basis", err)
+	}
+}
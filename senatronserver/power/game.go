@@ -0,0 +1,161 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"github.com/senatron/senatron/senatronserver/census"
+	"math"
+	"time"
+)
+
+// Game is a weighted voting game between states: a coalition of
+// states wins if their weights add up to at least the quota.  Weights
+// can't be negative.
+type Game struct {
+	States  []string
+	Weights []int
+	Quota   int
+}
+
+// senateQuota is the number of senate votes it takes to pass a
+// question by a majority of the full senate.
+const senateQuota = 51
+
+// populationUnit is the number of people that make up one unit of
+// weight in PopulationGame.
+const populationUnit = 1000
+
+// SenateGame returns the game the senate plays: every state casts
+// both its votes together, and it takes 51 votes to win.
+func SenateGame() Game {
+	states := census.RepresentedStates()
+	game := Game{
+		States:  states,
+		Weights: make([]int, len(states)),
+		Quota:   senateQuota,
+	}
+	for i := range states {
+		game.Weights[i] = 2
+	}
+	return game
+}
+
+// PopulationGame returns the game the senate would play if each state
+// cast a single block of votes weighted by its population (in
+// thousands) under the given basis at the given time, and it took
+// more than half the total population to win.
+func PopulationGame(basis census.Basis, at time.Time) (Game, error) {
+	states := census.RepresentedStates()
+	game := Game{
+		States:  states,
+		Weights: make([]int, len(states)),
+	}
+
+	total := 0
+	for i, state := range states {
+		population, err := census.GetBasisAt(basis, state, at)
+		if err != nil {
+			return Game{}, err
+		}
+		game.Weights[i] = int(
+			math.Floor(float64(population)/populationUnit + 0.5),
+		)
+		total += game.Weights[i]
+	}
+	game.Quota = total/2 + 1
+
+	return game, nil
+}
+
+// StateIndex is a single state's share of the voting power in a game.
+type StateIndex struct {
+	State         string  `json:"state"`
+	Weight        int     `json:"weight"`
+	Banzhaf       float64 `json:"banzhaf"`
+	ShapleyShubik float64 `json:"shapley_shubik"`
+}
+
+// Indices is the distribution of voting power in a game.
+type Indices struct {
+	Quota       int          `json:"quota"`
+	TotalWeight int          `json:"total_weight"`
+	States      []StateIndex `json:"states"`
+
+	// BanzhafExact and ShapleyShubikExact report whether each index
+	// was computed exactly, rather than estimated by sampling.
+	BanzhafExact       bool `json:"banzhaf_exact"`
+	ShapleyShubikExact bool `json:"shapley_shubik_exact"`
+}
+
+// Indices computes the Banzhaf and Shapley-Shubik indices of every
+// state in the game.
+func (g Game) Indices() Indices {
+	banzhaf, banzhafExact := g.Banzhaf()
+	shapleyShubik, shapleyShubikExact := g.ShapleyShubik()
+
+	out := Indices{
+		Quota:              g.Quota,
+		States:             make([]StateIndex, len(g.States)),
+		BanzhafExact:       banzhafExact,
+		ShapleyShubikExact: shapleyShubikExact,
+	}
+	for i, state := range g.States {
+		out.TotalWeight += g.Weights[i]
+		out.States[i] = StateIndex{
+			State:         state,
+			Weight:        g.Weights[i],
+			Banzhaf:       banzhaf[i],
+			ShapleyShubik: shapleyShubik[i],
+		}
+	}
+	return out
+}
+
+// maxExactCells caps the size of the tables the exact computations
+// fill in before they give up and fall back to sampling.
+const maxExactCells = 20000000
+
+// maxExactPlayers is the most players the exact computations can
+// handle before their coalition counts could overflow.
+const maxExactPlayers = 63
+
+// monteCarloSamples is the number of coalitions or orderings sampled
+// when an index can't be computed exactly.
+const monteCarloSamples = 200000
+
+// monteCarloSeed seeds the sampling, so the same game always gets the
+// same estimates.
+const monteCarloSeed = 1
+
+// normalize scales counts so they add up to 1, leaving them all 0 if
+// they're all 0 to begin with.
+func normalize(counts []float64) []float64 {
+	total := 0.0
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return counts
+	}
+	for i := range counts {
+		counts[i] /= total
+	}
+	return counts
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package power
+
+import (
+	"math"
+	"testing"
+)
+
+// knownGames are small games whose indices can be worked out by hand.
+var knownGames = []struct {
+	name          string
+	game          Game
+	banzhaf       []float64
+	shapleyShubik []float64
+}{
+	{
+		name:          "one big, two small",
+		game:          Game{Weights: []int{50, 49, 1}, Quota: 51},
+		banzhaf:       []float64{0.6, 0.2, 0.2},
+		shapleyShubik: []float64{2.0 / 3, 1.0 / 6, 1.0 / 6},
+	},
+	{
+		name:          "equal majority",
+		game:          Game{Weights: []int{1, 1, 1}, Quota: 2},
+		banzhaf:       []float64{1.0 / 3, 1.0 / 3, 1.0 / 3},
+		shapleyShubik: []float64{1.0 / 3, 1.0 / 3, 1.0 / 3},
+	},
+	{
+		name:          "dictator",
+		game:          Game{Weights: []int{3, 1, 1}, Quota: 3},
+		banzhaf:       []float64{1, 0, 0},
+		shapleyShubik: []float64{1, 0, 0},
+	},
+	{
+		name:          "dummy",
+		game:          Game{Weights: []int{2, 2, 1}, Quota: 4},
+		banzhaf:       []float64{0.5, 0.5, 0},
+		shapleyShubik: []float64{0.5, 0.5, 0},
+	},
+	{
+		name:          "weightless player",
+		game:          Game{Weights: []int{2, 0, 1}, Quota: 2},
+		banzhaf:       []float64{1, 0, 0},
+		shapleyShubik: []float64{1, 0, 0},
+	},
+	{
+		name:          "unreachable quota",
+		game:          Game{Weights: []int{1, 1}, Quota: 5},
+		banzhaf:       []float64{0, 0},
+		shapleyShubik: []float64{0, 0},
+	},
+}
+
+// midSizeWeights make a game too big to check by hand, but small
+// enough to compute exactly.
+var midSizeWeights = []int{
+	38, 27, 20, 19, 13, 12, 11, 10, 10, 9,
+	9, 8, 7, 7, 6, 6, 6, 5, 5, 4,
+	4, 4, 3, 3, 3, 2, 2, 2, 1, 1,
+}
+
+// sampledTolerance is how far a sampled index may stray from the exact
+// one in TestSampledMatchesExact.
+const sampledTolerance = 0.003
+
+// checkIndex fails the test if index differs from want by more than
+// tolerance for any player.
+func checkIndex(
+	t *testing.T,
+	what string,
+	index, want []float64,
+	tolerance float64,
+) {
+	if len(index) != len(want) {
+		t.Errorf("%s: Got %d players, want %d", what, len(index), len(want))
+		return
+	}
+	for i := range want {
+		if math.Abs(index[i]-want[i]) > tolerance {
+			t.Errorf("%s: Player %d got %v, want %v", what, i, index[i], want[i])
+		}
+	}
+}
+
+func TestSenateGame(t *testing.T) {
+	indices := SenateGame().Indices()
+	if indices.Quota != 51 || indices.TotalWeight != 100 {
+		t.Errorf("Got quota %d of %d", indices.Quota, indices.TotalWeight)
+	}
+	if !indices.BanzhafExact || !indices.ShapleyShubikExact {
+		t.Errorf("Senate game wasn't computed exactly")
+	}
+	for _, state := range indices.States {
+		if math.Abs(state.Banzhaf-0.02) > 1e-9 ||
+			math.Abs(state.ShapleyShubik-0.02) > 1e-9 {
+			t.Errorf("%+v: Want 0.02 for both indices", state)
+		}
+	}
+}
+
+func TestSampledMatchesExact(t *testing.T) {
+	total := 0
+	for _, weight := range midSizeWeights {
+		total += weight
+	}
+	exactGame := Game{Weights: midSizeWeights, Quota: total/2 + 1}
+
+	// Scaling every weight and the quota up leaves the game the same,
+	// but makes it too big to compute exactly.
+	const scale = 100000
+	sampledGame := Game{
+		Weights: make([]int, len(midSizeWeights)),
+		Quota:   exactGame.Quota * scale,
+	}
+	for i, weight := range midSizeWeights {
+		sampledGame.Weights[i] = weight * scale
+	}
+
+	exactBanzhaf, exact := exactGame.Banzhaf()
+	if !exact {
+		t.Fatalf("Banzhaf index of the unscaled game was sampled")
+	}
+	sampledBanzhaf, exact := sampledGame.Banzhaf()
+	if exact {
+		t.Fatalf("Banzhaf index of the scaled game was exact")
+	}
+	checkIndex(t, "Banzhaf", sampledBanzhaf, exactBanzhaf, sampledTolerance)
+
+	exactShapleyShubik, exact := exactGame.ShapleyShubik()
+	if !exact {
+		t.Fatalf("Shapley-Shubik index of the unscaled game was sampled")
+	}
+	sampledShapleyShubik, exact := sampledGame.ShapleyShubik()
+	if exact {
+		t.Fatalf("Shapley-Shubik index of the scaled game was exact")
+	}
+	checkIndex(
+		t,
+		"Shapley-Shubik",
+		sampledShapleyShubik,
+		exactShapleyShubik,
+		sampledTolerance,
+	)
+}
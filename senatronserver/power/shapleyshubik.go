@@ -0,0 +1,121 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ShapleyShubik returns each state's Shapley-Shubik index: the share
+// of all the orders states could join a coalition in where the state
+// is the one that takes it past the quota.  It's computed exactly from
+// the game's generating function when that's small enough, and
+// otherwise estimated by sampling orderings, in which case exact is
+// false.
+func (g Game) ShapleyShubik() (index []float64, exact bool) {
+	n := len(g.Weights)
+	if n <= maxExactPlayers && n*n*g.Quota <= maxExactCells {
+		return g.exactShapleyShubik(), true
+	}
+	return g.sampledShapleyShubik(), false
+}
+
+// exactShapleyShubik works like exactBanzhaf, but with a generating
+// function that counts coalitions by size as well as weight, so each
+// coalition of k others a state is pivotal for can be weighted by the
+// k!(n-k-1)!/n! orderings in which it joins right after them.
+func (g Game) exactShapleyShubik() []float64 {
+	n := len(g.Weights)
+	index := make([]float64, n)
+	if g.Quota <= 0 || n == 0 {
+		return index
+	}
+
+	all := make([][]uint64, n+1)
+	for k := range all {
+		all[k] = make([]uint64, g.Quota)
+	}
+	all[0][0] = 1
+	for j, weight := range g.Weights {
+		for k := j; k >= 0; k-- {
+			for w := g.Quota - 1; w >= weight && w >= 0; w-- {
+				all[k+1][w] += all[k][w-weight]
+			}
+		}
+	}
+
+	others := make([][]uint64, n)
+	for k := range others {
+		others[k] = make([]uint64, g.Quota)
+	}
+	for i, weight := range g.Weights {
+		if weight <= 0 {
+			continue
+		}
+		for k := range others {
+			for w := range others[k] {
+				others[k][w] = all[k][w]
+				if k > 0 && w >= weight {
+					others[k][w] -= others[k-1][w-weight]
+				}
+			}
+		}
+
+		low := g.Quota - weight
+		if low < 0 {
+			low = 0
+		}
+		for k := range others {
+			orderings := 1 / (float64(n) * binomial(n-1, k))
+			for w := low; w < g.Quota; w++ {
+				index[i] += float64(others[k][w]) * orderings
+			}
+		}
+	}
+	return index
+}
+
+// sampledShapleyShubik estimates the Shapley-Shubik index by drawing
+// random orderings and counting who's pivotal in each one.
+func (g Game) sampledShapleyShubik() []float64 {
+	random := rand.New(rand.NewSource(monteCarloSeed))
+	pivots := make([]float64, len(g.Weights))
+
+	for sample := 0; sample < monteCarloSamples; sample++ {
+		total := 0
+		for _, i := range random.Perm(len(g.Weights)) {
+			total += g.Weights[i]
+			if total >= g.Quota {
+				pivots[i]++
+				break
+			}
+		}
+	}
+	return normalize(pivots)
+}
+
+// binomial returns n choose k as a float.
+func binomial(n, k int) float64 {
+	result, _ := math.Lgamma(float64(n + 1))
+	a, _ := math.Lgamma(float64(k + 1))
+	b, _ := math.Lgamma(float64(n - k + 1))
+	return math.Exp(result - a - b)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package power
+
+import (
+	"testing"
+)
+
+func TestShapleyShubik(t *testing.T) {
+	for _, test := range knownGames {
+		index, exact := test.game.ShapleyShubik()
+		if !exact {
+			t.Errorf("%s: Wasn't computed exactly", test.name)
+		}
+		checkIndex(t, test.name, index, test.shapleyShubik, 1e-9)
+	}
+}
+
+// TestShapleyShubikBruteForce checks the exact computation against
+// finding the pivotal player in every ordering of a small game.
+func TestShapleyShubikBruteForce(t *testing.T) {
+	game := Game{Weights: midSizeWeights[:8], Quota: 70}
+
+	pivots := make([]float64, len(game.Weights))
+	order := make([]int, len(game.Weights))
+	for i := range order {
+		order[i] = i
+	}
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(order) {
+			weight := 0
+			for _, i := range order {
+				weight += game.Weights[i]
+				if weight >= game.Quota {
+					pivots[i]++
+					return
+				}
+			}
+			return
+		}
+		for i := k; i < len(order); i++ {
+			order[k], order[i] = order[i], order[k]
+			permute(k + 1)
+			order[k], order[i] = order[i], order[k]
+		}
+	}
+	permute(0)
+
+	index, _ := game.ShapleyShubik()
+	checkIndex(t, "brute force", index, normalize(pivots), 1e-9)
+}
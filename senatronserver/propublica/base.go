@@ -0,0 +1,98 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package propublica
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/source"
+	"net/http"
+	"time"
+)
+
+// DefaultBaseURI is the root of the ProPublica Congress API.
+const DefaultBaseURI = "https://api.propublica.org/congress/v1"
+
+// Client fetches data from the ProPublica Congress API, translating
+// it into the same shapes the sunlight package uses.  It implements
+// source.VoteSource.
+type Client struct {
+	APIKey string
+
+	// BaseURI is the root that all endpoints are resolved against.
+	// It only needs changing to point the client at a test server.
+	BaseURI string
+}
+
+var _ source.VoteSource = (*Client)(nil)
+
+// New returns a Client that will authenticate with the given API key.
+func New(apiKey string) *Client {
+	return &Client{APIKey: apiKey, BaseURI: DefaultBaseURI}
+}
+
+// errNotFound is returned by get when the API responds with a 404.
+var errNotFound = errors.New("propublica: Not found")
+
+// get fetches the given endpoint and decodes the "results" member of
+// the JSON response into out.
+func (c *Client) get(endpoint string, out interface{}) error {
+	request, err := http.NewRequest("GET", c.BaseURI+"/"+endpoint, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("X-API-Key", c.APIKey)
+
+	client := &http.Client{Timeout: time.Second * 10}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return errNotFound
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("propublica: %s", response.Status)
+	}
+
+	container := struct {
+		Status string `json:"status"`
+		Errors []struct {
+			Error string `json:"error"`
+		} `json:"errors"`
+		Results json.RawMessage `json:"results"`
+	}{}
+
+	decoder := json.NewDecoder(response.Body)
+	err = decoder.Decode(&container)
+	if err != nil {
+		return err
+	}
+
+	if container.Status != "OK" {
+		if len(container.Errors) > 0 {
+			return fmt.Errorf("propublica: %s", container.Errors[0].Error)
+		}
+		return errNotFound
+	}
+
+	return json.Unmarshal(container.Results, out)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package propublica
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// splitID splits a Sunlight-style bill or nomination ID (like
+// "hr3590-111") into the bare ID ProPublica uses and its congress.
+// The bare ID comes back escaped, ready to go into a URL path.
+func splitID(id string) (bare string, congress int, ok bool) {
+	i := strings.LastIndex(id, "-")
+	if i <= 0 {
+		return
+	}
+	congress, err := strconv.Atoi(id[i+1:])
+	if err != nil || congress <= 0 {
+		return "", 0, false
+	}
+	return url.PathEscape(id[:i]), congress, true
+}
+
+// GetBill returns information about the bill with the given ID (like
+// "hr3590-111"), or returns an error if anything goes wrong.
+func (c *Client) GetBill(billID string) (bill sunlight.Bill, err error) {
+	slug, congress, ok := splitID(billID)
+	if !ok {
+		err = sunlight.ErrBillNotFound
+		return
+	}
+
+	results := []struct {
+		BillID         string `json:"bill_id"`
+		Title          string `json:"title"`
+		ShortTitle     string `json:"short_title"`
+		SponsorID      string `json:"sponsor_id"`
+		Sponsor        string `json:"sponsor"`
+		SponsorParty   string `json:"sponsor_party"`
+		SponsorState   string `json:"sponsor_state"`
+		PrimarySubject string `json:"primary_subject"`
+	}{}
+
+	err = c.get(fmt.Sprintf("%d/bills/%s.json", congress, slug), &results)
+	if err == errNotFound || (err == nil && len(results) == 0) {
+		err = sunlight.ErrBillNotFound
+		return
+	} else if err != nil {
+		return
+	}
+
+	result := results[0]
+	bill = sunlight.Bill{
+		BillID:        result.BillID,
+		OfficialTitle: result.Title,
+		ShortTitle:    result.ShortTitle,
+		Sponsor: sunlight.Sponsor{
+			BioguideID: result.SponsorID,
+			Name:       result.Sponsor,
+			Party:      result.SponsorParty,
+			State:      result.SponsorState,
+		},
+		Subjects: []string{},
+	}
+
+	// The full list of subjects lives at its own endpoint, and
+	// plenty of bills don't have any, so the primary subject is a
+	// fallback.
+	subjects := []struct {
+		Subjects []struct {
+			Name string `json:"name"`
+		} `json:"subjects"`
+	}{}
+	err = c.get(
+		fmt.Sprintf("%d/bills/%s/subjects.json", congress, slug),
+		&subjects,
+	)
+	if err != nil && err != errNotFound {
+		return
+	}
+	err = nil
+
+	if len(subjects) > 0 {
+		for _, subject := range subjects[0].Subjects {
+			bill.Subjects = append(bill.Subjects, subject.Name)
+		}
+	}
+	if len(bill.Subjects) == 0 && result.PrimarySubject != "" {
+		bill.Subjects = append(bill.Subjects, result.PrimarySubject)
+	}
+	return
+}
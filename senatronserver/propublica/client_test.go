@@ -0,0 +1,393 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package propublica
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixtures maps the API paths the test server answers to the files in
+// testdata it answers them with.  Anything else gets not_found.json
+// with a 404.
+var fixtures = map[string]string{
+	"/115/senate/sessions/1/votes/17.json":     "senate_vote.json",
+	"/115/house/sessions/1/votes/99.json":      "house_vote.json",
+	"/115/house/sessions/1/votes/1.json":       "speaker_vote.json",
+	"/senate/votes/recent.json":                "listing.json",
+	"/senate/votes/2017-01-20/2017-01-23.json": "listing.json",
+	"/members/B001230.json":                    "member.json",
+	"/115/bills/hr72.json":                     "bill.json",
+	"/115/bills/hr72/subjects.json":            "not_found.json",
+	"/members/Z999999/votes.json":              "",
+	"/115/senate/sessions/1/votes/500.json":    "",
+	"/senate/votes/2016-01-01/2016-12-31.json": "",
+}
+
+// emptyListing is what the test server answers listings with past
+// their first page.
+const emptyListing = `{"status": "OK", "results": {"votes": []}}`
+
+// newTestClient starts a server answering with the fixtures and
+// returns a client pointed at it.  Paths mapped to an empty file name
+// fail with a 500.  The fixtures are all read up front, since the
+// server's goroutine can't fail the test.
+func newTestClient(t *testing.T) (*Client, func()) {
+	bodies := map[string][]byte{}
+	for _, name := range fixtures {
+		if _, ok := bodies[name]; ok || name == "" {
+			continue
+		}
+		body, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies[name] = body
+	}
+	notFound, err := ioutil.ReadFile(filepath.Join("testdata", "not_found.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-API-Key") != "test-key" {
+				t.Errorf("Request for %s without API key", r.URL.Path)
+			}
+
+			name, ok := fixtures[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write(notFound)
+				return
+			} else if name == "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			} else if offset := r.URL.Query().Get("offset"); offset != "" &&
+				offset != "0" {
+				w.Write([]byte(emptyListing))
+				return
+			} else if name == "not_found.json" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+			w.Write(bodies[name])
+		},
+	))
+
+	client := New("test-key")
+	client.BaseURI = server.URL
+	return client, server.Close
+}
+
+func TestGetVoteSenate(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	vote, err := client.GetVote("s17-2017")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votedAt := time.Date(2017, time.January, 20, 21, 30, 0, 0, time.UTC)
+	if vote.RollID != "s17-2017" ||
+		vote.Chamber != sunlight.Senate ||
+		vote.Congress != 115 ||
+		vote.Session != 1 ||
+		!vote.VotedAt.Equal(votedAt) ||
+		vote.Required != "3/5" ||
+		vote.BillID != "hr72-115" ||
+		vote.Result != "Cloture Motion Agreed to" {
+		t.Errorf("Wrong vote details: %+v", vote)
+	}
+	if vote.Question != "On the Cloture Motion: A bill to ensure the "+
+		"Government Accountability Office has adequate access to "+
+		"information." {
+		t.Errorf("Wrong question %q", vote.Question)
+	}
+
+	expected := map[string]sunlight.Voter{
+		"A000360": {
+			Vote: sunlight.Yes,
+			Info: sunlight.VoterInfo{
+				BioguideID: "A000360",
+				State:      "TN",
+				Party:      "R",
+			},
+		},
+		"B001230": {
+			Vote: sunlight.No,
+			Info: sunlight.VoterInfo{
+				BioguideID: "B001230",
+				State:      "WI",
+				Party:      "D",
+			},
+		},
+		"S000033": {
+			Vote: sunlight.NotVoting,
+			Info: sunlight.VoterInfo{
+				BioguideID: "S000033",
+				State:      "VT",
+				Party:      "ID",
+			},
+		},
+	}
+	if len(vote.Voters) != len(expected) {
+		t.Errorf("Got %d voters, expected %d", len(vote.Voters), len(expected))
+	}
+	for id, voter := range expected {
+		if vote.Voters[id] != voter {
+			t.Errorf("Voter %s is %+v, expected %+v", id, vote.Voters[id], voter)
+		}
+	}
+}
+
+func TestGetVoteHouse(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	vote, err := client.GetVote("h99-2017")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vote.Chamber != sunlight.House || vote.BillID != "hr1101-115" {
+		t.Errorf("Wrong vote details: %+v", vote)
+	}
+
+	cases := []struct {
+		id       string
+		position sunlight.Position
+		district int
+	}{
+		{"Y000033", sunlight.Yes, 0},
+		{"P000197", sunlight.No, 12},
+		{"G000578", sunlight.Present, 1},
+	}
+	for _, c := range cases {
+		voter := vote.Voters[c.id]
+		if voter.Vote != c.position || voter.Info.District != c.district {
+			t.Errorf(
+				"Voter %s is %+v, expected %s from district %d",
+				c.id,
+				voter,
+				c.position,
+				c.district,
+			)
+		}
+	}
+}
+
+func TestGetVoteErrors(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	cases := []struct {
+		rollID   string
+		notFound bool
+	}{
+		{"s18-2017", true},
+		{"bogus", true},
+		{"s500-2017", false},
+	}
+	for _, c := range cases {
+		_, err := client.GetVote(c.rollID)
+		if c.notFound && err != sunlight.ErrVoteNotFound {
+			t.Errorf("GetVote(%q) returned %v, expected not found", c.rollID, err)
+		} else if !c.notFound && (err == nil || err == sunlight.ErrVoteNotFound) {
+			t.Errorf("GetVote(%q) returned %v, expected failure", c.rollID, err)
+		}
+	}
+
+	_, err := client.GetVote("h1-2017")
+	if _, ok := err.(*sunlight.MalformedVoteError); !ok {
+		t.Errorf("Speaker election returned %v, expected a malformed vote", err)
+	}
+}
+
+func TestListVotes(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	since := time.Date(2017, time.January, 20, 12, 0, 0, 0, time.UTC)
+	until := time.Date(2017, time.January, 23, 23, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		query    sunlight.VoteQuery
+		expected []string
+	}{
+		{
+			"recent",
+			sunlight.VoteQuery{},
+			[]string{"s19-2017", "s18-2017", "s17-2017"},
+		},
+		{
+			"question",
+			sunlight.VoteQuery{Question: "nomination"},
+			[]string{"s18-2017"},
+		},
+		{
+			"bill",
+			sunlight.VoteQuery{BillID: "hr72-115"},
+			[]string{"s19-2017", "s17-2017"},
+		},
+		{
+			"page",
+			sunlight.VoteQuery{Page: 2, PerPage: 1},
+			[]string{"s18-2017"},
+		},
+		{
+			"date range",
+			sunlight.VoteQuery{Since: since, Until: until, Result: "bill passed"},
+			[]string{"s19-2017"},
+		},
+	}
+	for _, c := range cases {
+		votes, err := client.ListVotes(c.query)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+
+		rollIDs := make([]string, len(votes))
+		for i, vote := range votes {
+			rollIDs[i] = vote.RollID
+		}
+		if len(rollIDs) != len(c.expected) {
+			t.Errorf("%s: Got %v, expected %v", c.name, rollIDs, c.expected)
+			continue
+		}
+		for i := range rollIDs {
+			if rollIDs[i] != c.expected[i] {
+				t.Errorf("%s: Got %v, expected %v", c.name, rollIDs, c.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestListVotesUpstreamError(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	queries := []sunlight.VoteQuery{
+		{Voter: "Z999999"},
+		{
+			Since: time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2016, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, query := range queries {
+		_, err := client.ListVotes(query)
+		if err == nil {
+			t.Errorf("ListVotes(%+v) succeeded against a failing server", query)
+		}
+	}
+}
+
+func TestGetLegislator(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	legislator, err := client.GetLegislator("B001230")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legislator.BioguideID != "B001230" ||
+		legislator.FirstName != "Tammy" ||
+		legislator.LastName != "Baldwin" ||
+		legislator.Party != "D" ||
+		legislator.State != "WI" ||
+		legislator.Chamber != sunlight.Senate ||
+		legislator.TermStart != "2017-01-03" {
+		t.Errorf("Wrong legislator details: %+v", legislator)
+	}
+
+	expected := []sunlight.Term{
+		{
+			Start:   "2011-01-05",
+			End:     "2013-01-03",
+			Chamber: sunlight.House,
+			State:   "WI",
+			Party:   "D",
+		},
+		{
+			Start:   "2017-01-03",
+			End:     "2019-01-03",
+			Chamber: sunlight.Senate,
+			State:   "WI",
+			Party:   "D",
+		},
+	}
+	if len(legislator.Terms) != len(expected) {
+		t.Fatalf("Got terms %+v, expected %+v", legislator.Terms, expected)
+	}
+	for i := range expected {
+		if legislator.Terms[i] != expected[i] {
+			t.Errorf("Term %d is %+v, expected %+v", i, legislator.Terms[i], expected[i])
+		}
+	}
+
+	// "B001230.json?" would find Baldwin if it went upstream as is.
+	for _, id := range []string{"Z999999", "b001230", "B001230.json?", "../B001230"} {
+		_, err = client.GetLegislator(id)
+		if err != sunlight.ErrLegislatorNotFound {
+			t.Errorf("GetLegislator(%q) returned %v, expected not found", id, err)
+		}
+	}
+}
+
+func TestGetBill(t *testing.T) {
+	client, done := newTestClient(t)
+	defer done()
+
+	bill, err := client.GetBill("hr72-115")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bill.BillID != "hr72-115" ||
+		bill.ShortTitle != "GAO Access and Oversight Act of 2017" ||
+		bill.Sponsor.BioguideID != "C001103" ||
+		bill.Sponsor.State != "GA" {
+		t.Errorf("Wrong bill details: %+v", bill)
+	}
+	if len(bill.Subjects) != 1 ||
+		bill.Subjects[0] != "Government Operations and Politics" {
+		t.Errorf("Expected the primary subject, got %v", bill.Subjects)
+	}
+
+	// "hr72.json?-115" would find hr72 if it weren't escaped.
+	for _, id := range []string{
+		"hr1-115",
+		"bogus",
+		"-115",
+		"hr72-x",
+		"hr72.json?-115",
+	} {
+		_, err = client.GetBill(id)
+		if err != sunlight.ErrBillNotFound {
+			t.Errorf("GetBill(%q) returned %v, expected not found", id, err)
+		}
+	}
+}
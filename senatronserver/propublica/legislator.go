@@ -0,0 +1,91 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package propublica
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"regexp"
+)
+
+// bioguideIDPattern matches bioguide IDs, like "B001230".
+var bioguideIDPattern = regexp.MustCompile(`^[A-Z]\d{6}$`)
+
+// GetLegislator returns information about the legislator with the
+// given bioguide ID, or returns an error if anything goes wrong.  IDs
+// that aren't in the bioguide's format aren't looked up at all.
+func (c *Client) GetLegislator(
+	bioguideID string,
+) (legislator sunlight.Legislator, err error) {
+	if !bioguideIDPattern.MatchString(bioguideID) {
+		err = sunlight.ErrLegislatorNotFound
+		return
+	}
+
+	results := []struct {
+		ID        string `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Party     string `json:"current_party"`
+		Roles     []struct {
+			Chamber   string `json:"chamber"`
+			State     string `json:"state"`
+			Party     string `json:"party"`
+			StartDate string `json:"start_date"`
+			EndDate   string `json:"end_date"`
+		} `json:"roles"`
+	}{}
+
+	err = c.get("members/"+bioguideID+".json", &results)
+	if err == errNotFound || (err == nil && len(results) == 0) {
+		err = sunlight.ErrLegislatorNotFound
+		return
+	} else if err != nil {
+		return
+	}
+
+	member := results[0]
+	legislator = sunlight.Legislator{
+		BioguideID: member.ID,
+		FirstName:  member.FirstName,
+		LastName:   member.LastName,
+		Party:      member.Party,
+	}
+
+	// Roles come most recent first, and there's one for every
+	// congress served, so they're reversed into terms.
+	if len(member.Roles) > 0 {
+		legislator.State = member.Roles[0].State
+		legislator.Chamber = chamberName(member.Roles[0].Chamber)
+		legislator.TermStart = member.Roles[0].StartDate
+		legislator.TermEnd = member.Roles[0].EndDate
+	}
+	legislator.Terms = make([]sunlight.Term, 0, len(member.Roles))
+	for i := len(member.Roles) - 1; i >= 0; i-- {
+		role := member.Roles[i]
+		legislator.Terms = append(legislator.Terms, sunlight.Term{
+			Start:   role.StartDate,
+			End:     role.EndDate,
+			Chamber: chamberName(role.Chamber),
+			State:   role.State,
+			Party:   role.Party,
+		})
+	}
+	return
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package propublica
+
+import (
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+)
+
+// GetNomination returns information about the nomination with the
+// given ID (like "PN1234-113"), or returns an error if anything goes
+// wrong.  ProPublica only describes nominations as a whole, so the
+// returned nomination has a Description rather than Nominees.
+func (c *Client) GetNomination(
+	nominationID string,
+) (nomination sunlight.Nomination, err error) {
+	id, congress, ok := splitID(nominationID)
+	if !ok {
+		err = sunlight.ErrNominationNotFound
+		return
+	}
+
+	results := []struct {
+		Description  string `json:"description"`
+		Organization string `json:"organization"`
+	}{}
+
+	err = c.get(
+		fmt.Sprintf("%d/nominations/%s.json", congress, id),
+		&results,
+	)
+	if err == errNotFound || (err == nil && len(results) == 0) {
+		err = sunlight.ErrNominationNotFound
+		return
+	} else if err != nil {
+		return
+	}
+
+	nomination = sunlight.Nomination{
+		NominationID: nominationID,
+		Organization: results[0].Organization,
+		Description:  results[0].Description,
+		Nominees:     []sunlight.Nominee{},
+	}
+	return
+}
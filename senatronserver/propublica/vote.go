@@ -0,0 +1,331 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package propublica
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"log"
+	"strconv"
+	"time"
+)
+
+// vote mirrors a roll call vote as the ProPublica API returns it.
+// Listings return the same shape, minus the positions.
+type vote struct {
+	Congress int    `json:"congress"`
+	Session  int    `json:"session"`
+	Chamber  string `json:"chamber"`
+	RollCall int    `json:"roll_call"`
+
+	Question    string `json:"question"`
+	Description string `json:"description"`
+	VoteType    string `json:"vote_type"`
+	Date        string `json:"date"`
+	Time        string `json:"time"`
+	Result      string `json:"result"`
+
+	Bill struct {
+		BillID string `json:"bill_id"`
+	} `json:"bill"`
+	Nomination struct {
+		NominationID string `json:"nomination_id"`
+	} `json:"nomination"`
+
+	Positions []struct {
+		MemberID     string          `json:"member_id"`
+		Party        string          `json:"party"`
+		State        string          `json:"state"`
+		District     json.RawMessage `json:"district"`
+		VotePosition string          `json:"vote_position"`
+	} `json:"positions"`
+}
+
+// parseDistrict reads a House member's district, which ProPublica
+// gives either as a number or as a string like "3" or "At-Large".
+// At-large seats, senators (who have no district) and anything
+// unrecognisable all come out as 0.
+func parseDistrict(raw json.RawMessage) int {
+	var district interface{}
+	if json.Unmarshal(raw, &district) != nil {
+		return 0
+	}
+
+	switch typed := district.(type) {
+	case float64:
+		return int(typed)
+	case string:
+		n, err := strconv.Atoi(typed)
+		if err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// toSunlight converts the vote to the sunlight package's format.
+func (v vote) toSunlight() (out sunlight.Vote, err error) {
+	votedAt, err := time.ParseInLocation(
+		"2006-01-02 15:04:05",
+		v.Date+" "+v.Time,
+		sunlight.Eastern,
+	)
+	if err != nil {
+		// Without a date there's no year to build a roll ID from.
+		err = &sunlight.MalformedVoteError{
+			Field: "date",
+			Value: v.Date + " " + v.Time,
+		}
+		return
+	}
+
+	out = sunlight.Vote{
+		RollID: sunlight.RollID(
+			chamberName(v.Chamber),
+			v.RollCall,
+			votedAt.Year(),
+		),
+		Chamber:      chamberName(v.Chamber),
+		Congress:     v.Congress,
+		Session:      v.Session,
+		VotedAt:      votedAt.UTC(),
+		RollType:     v.Question,
+		Question:     v.Question,
+		Required:     v.VoteType,
+		Result:       v.Result,
+		BillID:       v.Bill.BillID,
+		NominationID: v.Nomination.NominationID,
+		Voters:       make(map[string]sunlight.Voter, len(v.Positions)),
+	}
+	if v.Description != "" {
+		out.Question += ": " + v.Description
+	}
+
+	for _, p := range v.Positions {
+		var position sunlight.Position
+		position, err = sunlight.ParsePosition(p.VotePosition)
+		if err != nil {
+			err = &sunlight.MalformedVoteError{
+				RollID: out.RollID,
+				Field:  "vote_position",
+				Value:  p.VotePosition,
+			}
+			return
+		}
+
+		out.Voters[p.MemberID] = sunlight.Voter{
+			Vote: position,
+			Info: sunlight.VoterInfo{
+				BioguideID: p.MemberID,
+				State:      p.State,
+				Party:      p.Party,
+				District:   parseDistrict(p.District),
+			},
+		}
+	}
+	return
+}
+
+// chamberName converts ProPublica's chamber names ("Senate", "House")
+// to the sunlight package's.
+func chamberName(chamber string) string {
+	if chamber == "House" {
+		return sunlight.House
+	}
+	return sunlight.Senate
+}
+
+// GetVote returns information about the given rollID, or returns an
+// error if anything goes wrong.  Malformed roll IDs can't name any
+// vote, so they get sunlight.ErrVoteNotFound.
+func (c *Client) GetVote(rollID string) (out sunlight.Vote, err error) {
+	chamber, number, year, err := sunlight.ParseRollID(rollID)
+	if err != nil {
+		err = sunlight.ErrVoteNotFound
+		return
+	}
+	congress, session := sunlight.CongressForYear(year)
+
+	container := struct {
+		Votes struct {
+			Vote vote `json:"vote"`
+		} `json:"votes"`
+	}{}
+
+	err = c.get(
+		fmt.Sprintf(
+			"%d/%s/sessions/%d/votes/%d.json",
+			congress,
+			chamber,
+			session,
+			number,
+		),
+		&container,
+	)
+	if err == errNotFound {
+		err = sunlight.ErrVoteNotFound
+		return
+	} else if err != nil {
+		return
+	}
+
+	return container.Votes.Vote.toSunlight()
+}
+
+// maxRecentPages caps how far back ListVotes will step through
+// ProPublica's recent votes looking for matches.
+const maxRecentPages = 25
+
+// ListVotes returns a page of votes matching the query, most recent
+// first.  ProPublica can only search by chamber and date range, so
+// everything else is filtered here; without both ends of a date range
+// only the most recent few hundred votes are searched.  When filtering
+// by voter, only that member's most recent few hundred votes (in the
+// given chamber, or the senate by default) are searched.  ProPublica's
+// listings don't include individual positions either, so the returned
+// votes have no Voters; use GetVote for the full record.
+func (c *Client) ListVotes(
+	query sunlight.VoteQuery,
+) (votes []sunlight.Vote, err error) {
+	chamber := query.Chamber
+	if chamber == "" {
+		chamber = sunlight.Senate
+	}
+
+	page, perPage := query.Pagination()
+	skip := (page - 1) * perPage
+	votes = make([]sunlight.Vote, 0, perPage)
+
+	// Listings have no positions to check the voter filter against,
+	// so that's left to the endpoint listing only that member's
+	// votes, which covers both chambers.
+	matcher := query
+	matcher.Voter = ""
+	if query.Voter != "" {
+		matcher.Chamber = chamber
+	}
+
+	// add filters a batch of votes from the API into the results,
+	// returning true once the requested page is full.  Malformed
+	// votes are logged and skipped.
+	add := func(batch []vote) (bool, error) {
+		for _, v := range batch {
+			converted, err := v.toSunlight()
+			if err != nil {
+				log.Printf("Skipping vote: %v", err)
+				continue
+			}
+			if !matcher.Matches(converted) {
+				continue
+			}
+
+			if skip > 0 {
+				skip--
+				continue
+			}
+			votes = append(votes, converted)
+			if len(votes) == perPage {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if query.Voter == "" && !query.Since.IsZero() && !query.Until.IsZero() {
+		var batch []vote
+		batch, err = c.getVotes(
+			fmt.Sprintf(
+				"%s/votes/%s/%s.json",
+				chamber,
+				query.Since.In(sunlight.Eastern).Format("2006-01-02"),
+				query.Until.In(sunlight.Eastern).Format("2006-01-02"),
+			),
+		)
+		if err != nil {
+			return
+		}
+		_, err = add(batch)
+		return
+	}
+
+	// Recent votes (and a member's votes) come in pages of a fixed
+	// 20, so we step through them by offset until we've filled the
+	// requested page.
+	offset := 0
+	for i := 0; i < maxRecentPages; i++ {
+		var batch []vote
+		if query.Voter != "" {
+			batch, err = c.getMemberVotes(query.Voter, offset)
+		} else {
+			batch, err = c.getVotes(
+				fmt.Sprintf(
+					"%s/votes/recent.json?offset=%d",
+					chamber,
+					offset,
+				),
+			)
+		}
+		if err != nil {
+			return
+		}
+
+		var full bool
+		full, err = add(batch)
+		if err != nil || full || len(batch) == 0 {
+			return
+		}
+		offset += len(batch)
+	}
+	return
+}
+
+// getVotes fetches a listing of votes from the given endpoint.
+func (c *Client) getVotes(endpoint string) ([]vote, error) {
+	container := struct {
+		Votes []vote `json:"votes"`
+	}{}
+
+	err := c.get(endpoint, &container)
+	return container.Votes, err
+}
+
+// getMemberVotes fetches a page of the votes the given member took
+// part in, starting at the given offset.
+func (c *Client) getMemberVotes(bioguideID string, offset int) ([]vote, error) {
+	results := []struct {
+		Votes []vote `json:"votes"`
+	}{}
+
+	err := c.get(
+		fmt.Sprintf("members/%s/votes.json?offset=%d", bioguideID, offset),
+		&results,
+	)
+	if err == errNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return results[0].Votes, nil
+}
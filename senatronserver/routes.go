@@ -51,6 +51,50 @@ func initRoutes(
 	r.NotFoundHandler = basicStack.ThenFunc(handlers.FourOhFour)
 
 	r.Handle("/", basicStack.Then(handlers.Index(globalContext)))
+	r.Handle("/votes/{rollID}", basicStack.Then(handlers.Vote(globalContext)))
+	r.Handle(
+		"/legislators/{bioguideID}",
+		basicStack.Then(handlers.Legislator(globalContext)),
+	)
+	r.Handle("/bills/{billID}", basicStack.Then(handlers.Bill(globalContext)))
+
+	api := r.PathPrefix("/api").Subrouter()
+	api.Handle(
+		"/votes",
+		basicStack.Then(handlers.APIVotes(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/votes/divergent",
+		basicStack.Then(handlers.APIDivergent(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/votes/{rollID}",
+		basicStack.Then(handlers.APIVote(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/votes/{rollID}/states",
+		basicStack.Then(handlers.APIVoteStates(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/votes/{rollID}/schemes",
+		basicStack.Then(handlers.APIVoteSchemes(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/votes/{rollID}/counterfactual",
+		basicStack.Then(handlers.APIVoteCounterfactual(globalContext)),
+	).Methods("POST")
+	api.Handle(
+		"/power",
+		basicStack.Then(handlers.APIPower(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/legislators/{bioguideID}",
+		basicStack.Then(handlers.APILegislator(globalContext)),
+	).Methods("GET")
+	api.Handle(
+		"/bills/{billID}/comparison",
+		basicStack.Then(handlers.APIBillComparison(globalContext)),
+	).Methods("GET")
 
 	staticHandler := func(subpath string) http.Handler {
 		return basicStack.Then(
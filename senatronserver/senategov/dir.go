@@ -0,0 +1,123 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package senategov
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ReadDir parses every senate.gov XML file in the given directory
+// (and its subdirectories).  Votes that only appear in a vote_menu are
+// included without their Voters, but a full roll_call_vote document
+// always takes precedence over a menu entry for the same vote.
+// Non-XML files are ignored.
+func ReadDir(dir string) ([]sunlight.Vote, error) {
+	full := map[string]sunlight.Vote{}
+	summaries := map[string]sunlight.Vote{}
+
+	err := filepath.Walk(
+		dir,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || filepath.Ext(path) != ".xml" {
+				return nil
+			}
+
+			fin, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer fin.Close()
+
+			root, err := rootElement(fin)
+			if err != nil {
+				return fmt.Errorf("senategov: %s: %v", path, err)
+			}
+			_, err = fin.Seek(0, io.SeekStart)
+			if err != nil {
+				return err
+			}
+
+			switch root {
+			case "roll_call_vote":
+				vote, err := ParseRollCallVote(fin)
+				if err != nil {
+					return fmt.Errorf("senategov: %s: %v", path, err)
+				}
+				full[vote.RollID] = vote
+
+			case "vote_summary":
+				votes, err := ParseVoteMenu(fin)
+				if err != nil {
+					return fmt.Errorf("senategov: %s: %v", path, err)
+				}
+				for _, vote := range votes {
+					summaries[vote.RollID] = vote
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for rollID, vote := range summaries {
+		if _, ok := full[rollID]; !ok {
+			full[rollID] = vote
+		}
+	}
+
+	votes := make([]sunlight.Vote, 0, len(full))
+	for _, vote := range full {
+		votes = append(votes, vote)
+	}
+	sort.Sort(byRollID(votes))
+	return votes, nil
+}
+
+// rootElement returns the name of the first element in an XML
+// document.
+func rootElement(r io.Reader) (string, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+type byRollID []sunlight.Vote
+
+func (v byRollID) Len() int           { return len(v) }
+func (v byRollID) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+func (v byRollID) Less(i, j int) bool { return v[i].RollID < v[j].RollID }
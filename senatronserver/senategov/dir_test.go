@@ -0,0 +1,82 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package senategov
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadDir(t *testing.T) {
+	votes, err := ReadDir(filepath.Join("testdata", "votes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The full documents for 17 and 18 win out over the menu entry
+	// for 18, and 19 only appears in the menu.
+	tests := []struct {
+		rollID string
+		voters int
+	}{
+		{"s17-2017", 4},
+		{"s18-2017", 2},
+		{"s19-2017", 0},
+	}
+	if len(votes) != len(tests) {
+		t.Fatalf("Got %d votes, want %d", len(votes), len(tests))
+	}
+	for i, test := range tests {
+		if votes[i].RollID != test.rollID ||
+			len(votes[i].Voters) != test.voters {
+			t.Errorf(
+				"Vote %d is %s with %d voters, want %s with %d",
+				i,
+				votes[i].RollID,
+				len(votes[i].Voters),
+				test.rollID,
+				test.voters,
+			)
+		}
+	}
+}
+
+func TestReadDirMalformed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "senategov")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "vote.xml"),
+		[]byte("<roll_call_vote><vote_date>never</vote_date></roll_call_vote>"),
+		0600,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ReadDir(dir)
+	if err == nil {
+		t.Errorf("Read a directory with a malformed vote")
+	}
+}
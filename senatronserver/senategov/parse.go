@@ -0,0 +1,226 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package senategov
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rollCallVote mirrors the roll_call_vote documents senate.gov
+// publishes for each individual vote.
+type rollCallVote struct {
+	XMLName      xml.Name `xml:"roll_call_vote"`
+	Congress     int      `xml:"congress"`
+	Session      int      `xml:"session"`
+	CongressYear int      `xml:"congress_year"`
+	VoteNumber   int      `xml:"vote_number"`
+	VoteDate     string   `xml:"vote_date"`
+
+	VoteQuestionText    string `xml:"vote_question_text"`
+	Question            string `xml:"question"`
+	MajorityRequirement string `xml:"majority_requirement"`
+	VoteResult          string `xml:"vote_result"`
+
+	Document struct {
+		Congress int    `xml:"document_congress"`
+		Type     string `xml:"document_type"`
+		Number   string `xml:"document_number"`
+	} `xml:"document"`
+
+	Members []struct {
+		LastName  string `xml:"last_name"`
+		FirstName string `xml:"first_name"`
+		Party     string `xml:"party"`
+		State     string `xml:"state"`
+		VoteCast  string `xml:"vote_cast"`
+		LISID     string `xml:"lis_member_id"`
+	} `xml:"members>member"`
+}
+
+// billTypes maps senate.gov document types onto the prefixes used in
+// Sunlight-style bill IDs.
+var billTypes = map[string]string{
+	"S.":         "s",
+	"S.Res.":     "sres",
+	"S.J.Res.":   "sjres",
+	"S.Con.Res.": "sconres",
+	"H.R.":       "hr",
+	"H.Res.":     "hres",
+	"H.J.Res.":   "hjres",
+	"H.Con.Res.": "hconres",
+}
+
+// ParseRollCallVote reads a single roll_call_vote document.  Senate
+// XML only identifies senators by their LIS member ID, so that's what
+// the returned vote's Voters are keyed by, and their BioguideIDs are
+// left empty.
+func ParseRollCallVote(r io.Reader) (vote sunlight.Vote, err error) {
+	document := rollCallVote{}
+	err = xml.NewDecoder(r).Decode(&document)
+	if err != nil {
+		return
+	}
+
+	rollID := sunlight.RollID(
+		sunlight.Senate,
+		document.VoteNumber,
+		document.CongressYear,
+	)
+
+	// Dates look like "January 3, 2017,  12:00 PM", sometimes with
+	// extra spaces.
+	votedAt, err := time.ParseInLocation(
+		"January 2, 2006, 3:04 PM",
+		strings.Join(strings.Fields(document.VoteDate), " "),
+		sunlight.Eastern,
+	)
+	if err != nil {
+		err = &sunlight.MalformedVoteError{
+			RollID: rollID,
+			Field:  "vote_date",
+			Value:  document.VoteDate,
+		}
+		return
+	}
+
+	vote = sunlight.Vote{
+		RollID:   rollID,
+		Chamber:  sunlight.Senate,
+		Congress: document.Congress,
+		Session:  document.Session,
+		VotedAt:  votedAt.UTC(),
+		RollType: document.Question,
+		Question: document.VoteQuestionText,
+		Required: document.MajorityRequirement,
+		Result:   document.VoteResult,
+		Voters:   make(map[string]sunlight.Voter, len(document.Members)),
+	}
+
+	doc := document.Document
+	if doc.Type == "PN" {
+		vote.NominationID = fmt.Sprintf("PN%s-%d", doc.Number, doc.Congress)
+	} else if prefix, ok := billTypes[doc.Type]; ok {
+		vote.BillID = fmt.Sprintf("%s%s-%d", prefix, doc.Number, doc.Congress)
+	}
+
+	for _, m := range document.Members {
+		var position sunlight.Position
+		position, err = sunlight.ParsePosition(m.VoteCast)
+		if err != nil {
+			err = &sunlight.MalformedVoteError{
+				RollID: rollID,
+				Field:  "vote_cast",
+				Value:  m.VoteCast,
+			}
+			return
+		}
+
+		vote.Voters[m.LISID] = sunlight.Voter{
+			Vote: position,
+			Info: sunlight.VoterInfo{
+				LISID: m.LISID,
+				State: m.State,
+				Party: m.Party,
+			},
+		}
+	}
+	return
+}
+
+// voteMenu mirrors the vote_menu documents senate.gov publishes to
+// summarize every vote in a session.
+type voteMenu struct {
+	XMLName      xml.Name `xml:"vote_summary"`
+	Congress     int      `xml:"congress"`
+	Session      int      `xml:"session"`
+	CongressYear int      `xml:"congress_year"`
+
+	Votes []struct {
+		VoteNumber string `xml:"vote_number"`
+		VoteDate   string `xml:"vote_date"`
+		Question   string `xml:"question"`
+		Result     string `xml:"result"`
+		Title      string `xml:"title"`
+	} `xml:"votes>vote"`
+}
+
+// ParseVoteMenu reads a vote_menu document.  Menus only summarize
+// each vote, so the returned votes have no Voters and their VotedAt
+// times only have day precision.
+func ParseVoteMenu(r io.Reader) (votes []sunlight.Vote, err error) {
+	document := voteMenu{}
+	err = xml.NewDecoder(r).Decode(&document)
+	if err != nil {
+		return
+	}
+
+	votes = make([]sunlight.Vote, 0, len(document.Votes))
+	for _, v := range document.Votes {
+		var number int
+		number, err = strconv.Atoi(v.VoteNumber)
+		if err != nil {
+			err = fmt.Errorf(
+				"senategov: Bad vote number %q: %v",
+				v.VoteNumber,
+				err,
+			)
+			return
+		}
+
+		// Menu dates look like "03-Jan", with the year implied by
+		// the menu itself.
+		var votedAt time.Time
+		votedAt, err = time.ParseInLocation(
+			"02-Jan 2006",
+			fmt.Sprintf("%s %d", v.VoteDate, document.CongressYear),
+			sunlight.Eastern,
+		)
+		if err != nil {
+			err = fmt.Errorf(
+				"senategov: Bad date on vote %d: %v",
+				number,
+				err,
+			)
+			return
+		}
+
+		votes = append(votes, sunlight.Vote{
+			RollID: sunlight.RollID(
+				sunlight.Senate,
+				number,
+				document.CongressYear,
+			),
+			Chamber:  sunlight.Senate,
+			Congress: document.Congress,
+			Session:  document.Session,
+			VotedAt:  votedAt.UTC(),
+			RollType: v.Question,
+			Question: v.Title,
+			Result:   v.Result,
+		})
+	}
+	return
+}
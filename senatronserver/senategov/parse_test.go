@@ -0,0 +1,188 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package senategov
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRollCallVote(t *testing.T) {
+	fin, err := os.Open(filepath.Join("testdata", "votes", "vote_115_1_00017.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fin.Close()
+
+	vote, err := ParseRollCallVote(fin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votedAt := time.Date(2017, time.January, 20, 21, 30, 0, 0, time.UTC)
+	if vote.RollID != "s17-2017" ||
+		vote.Chamber != sunlight.Senate ||
+		vote.Congress != 115 ||
+		vote.Session != 1 ||
+		!vote.VotedAt.Equal(votedAt) ||
+		vote.RollType != "On the Cloture Motion" ||
+		vote.Question != "On the Cloture Motion H.R. 72" ||
+		vote.Required != "3/5" ||
+		vote.Result != "Cloture Motion Agreed to" ||
+		vote.BillID != "hr72-115" ||
+		vote.NominationID != "" {
+		t.Errorf("Wrong vote details: %+v", vote)
+	}
+
+	want := map[string]sunlight.VoterInfo{
+		"S354": {LISID: "S354", State: "WI", Party: "D"},
+		"S317": {LISID: "S317", State: "WY", Party: "R"},
+		"S330": {LISID: "S330", State: "CO", Party: "D"},
+		"S313": {LISID: "S313", State: "VT", Party: "I"},
+	}
+	positions := map[string]sunlight.Position{
+		"S354": sunlight.Yes,
+		"S317": sunlight.No,
+		"S330": sunlight.NotVoting,
+		"S313": sunlight.Present,
+	}
+	if len(vote.Voters) != len(want) {
+		t.Errorf("Got %d voters, want %d", len(vote.Voters), len(want))
+	}
+	for id, info := range want {
+		voter, ok := vote.Voters[id]
+		if !ok {
+			t.Errorf("%s: Missing", id)
+			continue
+		}
+		if voter.Info != info || voter.Vote != positions[id] {
+			t.Errorf("%s: Got %+v", id, voter)
+		}
+	}
+}
+
+func TestParseRollCallVoteNomination(t *testing.T) {
+	fin, err := os.Open(filepath.Join("testdata", "votes", "vote_115_1_00018.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fin.Close()
+
+	vote, err := ParseRollCallVote(fin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vote.NominationID != "PN45-115" || vote.BillID != "" {
+		t.Errorf("Got bill %q, nomination %q", vote.BillID, vote.NominationID)
+	}
+}
+
+func TestParseRollCallVoteMalformed(t *testing.T) {
+	document := func(date, voteCast string) string {
+		return `<roll_call_vote>
+			<congress>115</congress>
+			<session>1</session>
+			<congress_year>2017</congress_year>
+			<vote_number>17</vote_number>
+			<vote_date>` + date + `</vote_date>
+			<members><member>
+				<state>WI</state>
+				<vote_cast>` + voteCast + `</vote_cast>
+				<lis_member_id>S354</lis_member_id>
+			</member></members>
+		</roll_call_vote>`
+	}
+
+	tests := []struct {
+		document string
+		field    string
+	}{
+		{document("2017-01-20", "Yea"), "vote_date"},
+		{document("January 20, 2017, 04:30 PM", "Abstain"), "vote_cast"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseRollCallVote(strings.NewReader(test.document))
+		malformed, ok := err.(*sunlight.MalformedVoteError)
+		if !ok {
+			t.Errorf("%s: Got %v, want a MalformedVoteError", test.field, err)
+			continue
+		}
+		if malformed.RollID != "s17-2017" || malformed.Field != test.field {
+			t.Errorf("%s: Got %+v", test.field, malformed)
+		}
+	}
+}
+
+func TestParseVoteMenu(t *testing.T) {
+	fin, err := os.Open(
+		filepath.Join("testdata", "votes", "menus", "vote_menu_115_1.xml"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fin.Close()
+
+	votes, err := ParseVoteMenu(fin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 2 {
+		t.Fatalf("Got %d votes, want 2", len(votes))
+	}
+
+	// Menu dates are midnight, Eastern time.
+	votedAt := time.Date(2017, time.January, 24, 5, 0, 0, 0, time.UTC)
+	vote := votes[0]
+	if vote.RollID != "s19-2017" ||
+		vote.Chamber != sunlight.Senate ||
+		vote.Congress != 115 ||
+		vote.Session != 1 ||
+		!vote.VotedAt.Equal(votedAt) ||
+		vote.RollType != "On the Nomination" ||
+		vote.Result != "Confirmed" ||
+		len(vote.Voters) != 0 {
+		t.Errorf("Wrong vote details: %+v", vote)
+	}
+	if votes[1].RollID != "s18-2017" {
+		t.Errorf("Got %s second, want s18-2017", votes[1].RollID)
+	}
+}
+
+func TestParseVoteMenuMalformed(t *testing.T) {
+	documents := []string{
+		`<vote_summary><congress_year>2017</congress_year><votes><vote>
+			<vote_number>seventeen</vote_number><vote_date>20-Jan</vote_date>
+		</vote></votes></vote_summary>`,
+		`<vote_summary><congress_year>2017</congress_year><votes><vote>
+			<vote_number>00017</vote_number><vote_date>Jan 20</vote_date>
+		</vote></votes></vote_summary>`,
+	}
+
+	for _, document := range documents {
+		_, err := ParseVoteMenu(strings.NewReader(document))
+		if err == nil {
+			t.Errorf("Parsed %s", document)
+		}
+	}
+}
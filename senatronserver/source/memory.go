@@ -0,0 +1,219 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"sort"
+	"sync"
+)
+
+// Memory is a VoteSource that serves votes, legislators, bills and
+// nominations from memory.  It's handy for tests and for data that's
+// been loaded from somewhere up front.
+type Memory struct {
+	mutex       sync.RWMutex
+	votes       map[string]sunlight.Vote
+	legislators map[string]sunlight.Legislator
+	bills       map[string]sunlight.Bill
+	nominations map[string]sunlight.Nomination
+}
+
+// NewMemory returns an empty Memory source.
+func NewMemory() *Memory {
+	return &Memory{
+		votes:       map[string]sunlight.Vote{},
+		legislators: map[string]sunlight.Legislator{},
+		bills:       map[string]sunlight.Bill{},
+		nominations: map[string]sunlight.Nomination{},
+	}
+}
+
+// AddVote stores a vote, replacing any existing vote with the same
+// roll ID.
+func (m *Memory) AddVote(vote sunlight.Vote) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.votes[vote.RollID] = vote
+}
+
+// AddLegislator stores a legislator, replacing any existing
+// legislator with the same bioguide ID.
+func (m *Memory) AddLegislator(legislator sunlight.Legislator) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.legislators[legislator.BioguideID] = legislator
+}
+
+// AddBill stores a bill, replacing any existing bill with the same
+// ID.
+func (m *Memory) AddBill(bill sunlight.Bill) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.bills[bill.BillID] = bill
+}
+
+// AddNomination stores a nomination, replacing any existing
+// nomination with the same ID.
+func (m *Memory) AddNomination(nomination sunlight.Nomination) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.nominations[nomination.NominationID] = nomination
+}
+
+// GetVote returns the vote with the given roll ID.
+func (m *Memory) GetVote(rollID string) (sunlight.Vote, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if vote, ok := m.votes[rollID]; ok {
+		return vote, nil
+	}
+	return sunlight.Vote{}, sunlight.ErrVoteNotFound
+}
+
+// ListVotes returns a page of stored votes matching the query, most
+// recent first.
+func (m *Memory) ListVotes(
+	query sunlight.VoteQuery,
+) ([]sunlight.Vote, error) {
+	return Select(m.allVotes(), query), nil
+}
+
+// EachVote passes every stored vote matching the query to fn, most
+// recent first, until fn returns false.
+func (m *Memory) EachVote(
+	query sunlight.VoteQuery,
+	fn func(sunlight.Vote) bool,
+) error {
+	for _, vote := range Matching(m.allVotes(), query) {
+		if !fn(vote) {
+			break
+		}
+	}
+	return nil
+}
+
+// allVotes returns a copy of every stored vote, in no particular
+// order.
+func (m *Memory) allVotes() []sunlight.Vote {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	votes := make([]sunlight.Vote, 0, len(m.votes))
+	for _, vote := range m.votes {
+		votes = append(votes, vote)
+	}
+	return votes
+}
+
+// GetLegislator returns the legislator with the given bioguide ID.
+func (m *Memory) GetLegislator(
+	bioguideID string,
+) (sunlight.Legislator, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if legislator, ok := m.legislators[bioguideID]; ok {
+		return legislator, nil
+	}
+	return sunlight.Legislator{}, sunlight.ErrLegislatorNotFound
+}
+
+// GetBill returns the bill with the given ID.
+func (m *Memory) GetBill(billID string) (sunlight.Bill, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if bill, ok := m.bills[billID]; ok {
+		return bill, nil
+	}
+	return sunlight.Bill{}, sunlight.ErrBillNotFound
+}
+
+// GetNomination returns the nomination with the given ID.
+func (m *Memory) GetNomination(
+	nominationID string,
+) (sunlight.Nomination, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if nomination, ok := m.nominations[nominationID]; ok {
+		return nomination, nil
+	}
+	return sunlight.Nomination{}, sunlight.ErrNominationNotFound
+}
+
+// Select filters a set of votes with the query, then returns the page
+// of matching votes the query asks for, most recent first.  It's for
+// sources that keep all their votes at hand and don't have any better
+// way of searching them.
+func Select(
+	votes []sunlight.Vote,
+	query sunlight.VoteQuery,
+) []sunlight.Vote {
+	return paginate(Matching(votes, query), query)
+}
+
+// Matching filters a set of votes with the query, ignoring its
+// pagination, and returns every matching vote, most recent first.
+func Matching(
+	votes []sunlight.Vote,
+	query sunlight.VoteQuery,
+) []sunlight.Vote {
+	matching := make([]sunlight.Vote, 0, len(votes))
+	for _, vote := range votes {
+		if query.Matches(vote) {
+			matching = append(matching, vote)
+		}
+	}
+
+	sort.Sort(byMostRecent(matching))
+	return matching
+}
+
+// paginate returns the page of votes selected by the query.
+func paginate(
+	votes []sunlight.Vote,
+	query sunlight.VoteQuery,
+) []sunlight.Vote {
+	page, perPage := query.Pagination()
+
+	start := (page - 1) * perPage
+	if start >= len(votes) {
+		return []sunlight.Vote{}
+	}
+	end := start + perPage
+	if end > len(votes) {
+		end = len(votes)
+	}
+	return votes[start:end]
+}
+
+type byMostRecent []sunlight.Vote
+
+func (v byMostRecent) Len() int      { return len(v) }
+func (v byMostRecent) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v byMostRecent) Less(i, j int) bool {
+	if !v[i].VotedAt.Equal(v[j].VotedAt) {
+		return v[i].VotedAt.After(v[j].VotedAt)
+	}
+	return v[i].RollID < v[j].RollID
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"sync"
+	"time"
+)
+
+// metadataTTL is how long CachedMetadata holds on to a bill or
+// nomination, or the fact that there isn't one, before looking it up
+// again.
+const metadataTTL = 24 * time.Hour
+
+// maxCachedMetadata caps the number of bills and nominations
+// CachedMetadata holds on to at once.
+const maxCachedMetadata = 1000
+
+// CachedMetadata is a VoteSource that holds on to the bills and
+// nominations it looks up in memory, so that showing a vote doesn't
+// cost another request upstream for its subject every time.  Missing
+// bills and nominations are remembered too, but failed lookups aren't.
+// Everything else goes straight to the wrapped source.
+type CachedMetadata struct {
+	VoteSource
+
+	mutex   sync.Mutex
+	entries map[string]metadataEntry
+}
+
+// metadataEntry is a bill or nomination (or a not found error) held
+// by CachedMetadata.
+type metadataEntry struct {
+	value     interface{}
+	err       error
+	fetchedAt time.Time
+}
+
+// NewCachedMetadata returns a CachedMetadata source wrapping the given
+// one.
+func NewCachedMetadata(upstream VoteSource) *CachedMetadata {
+	return &CachedMetadata{
+		VoteSource: upstream,
+		entries:    map[string]metadataEntry{},
+	}
+}
+
+// GetBill returns the bill with the given ID, from memory if it's been
+// looked up lately.
+func (c *CachedMetadata) GetBill(billID string) (sunlight.Bill, error) {
+	value, err := c.lookup(
+		"bill/"+billID,
+		sunlight.ErrBillNotFound,
+		func() (interface{}, error) {
+			return c.VoteSource.GetBill(billID)
+		},
+	)
+	if err != nil {
+		return sunlight.Bill{}, err
+	}
+	return value.(sunlight.Bill), nil
+}
+
+// GetNomination returns the nomination with the given ID, from memory
+// if it's been looked up lately.
+func (c *CachedMetadata) GetNomination(
+	nominationID string,
+) (sunlight.Nomination, error) {
+	value, err := c.lookup(
+		"nomination/"+nominationID,
+		sunlight.ErrNominationNotFound,
+		func() (interface{}, error) {
+			return c.VoteSource.GetNomination(nominationID)
+		},
+	)
+	if err != nil {
+		return sunlight.Nomination{}, err
+	}
+	return value.(sunlight.Nomination), nil
+}
+
+// lookup returns the entry under key if there's a fresh one, and
+// otherwise calls fetch and holds on to whatever it returns, as long
+// as it either succeeded or failed with notFound.  The mutex isn't
+// held while fetching, so two requests for the same thing at once may
+// both go upstream.
+func (c *CachedMetadata) lookup(
+	key string,
+	notFound error,
+	fetch func() (interface{}, error),
+) (interface{}, error) {
+	now := time.Now()
+
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	c.mutex.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < metadataTTL {
+		return entry.value, entry.err
+	}
+
+	value, err := fetch()
+	if err != nil && err != notFound {
+		return value, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCachedMetadata {
+		c.evict(now)
+	}
+	c.entries[key] = metadataEntry{value: value, err: err, fetchedAt: now}
+	return value, err
+}
+
+// evict makes room for a new entry by dropping every stale entry, or
+// an arbitrary one if none of them are stale.  The mutex must be held.
+func (c *CachedMetadata) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) >= metadataTTL {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < maxCachedMetadata {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"errors"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"testing"
+)
+
+// countingSource is a VoteSource that counts the bill and nomination
+// lookups that reach it, failing them while fail is set.
+type countingSource struct {
+	VoteSource
+	bills, nominations int
+	fail               bool
+}
+
+var errUpstream = errors.New("Upstream is down")
+
+func (s *countingSource) GetBill(billID string) (sunlight.Bill, error) {
+	s.bills++
+	if s.fail {
+		return sunlight.Bill{}, errUpstream
+	}
+	return s.VoteSource.GetBill(billID)
+}
+
+func (s *countingSource) GetNomination(
+	nominationID string,
+) (sunlight.Nomination, error) {
+	s.nominations++
+	if s.fail {
+		return sunlight.Nomination{}, errUpstream
+	}
+	return s.VoteSource.GetNomination(nominationID)
+}
+
+func TestCachedMetadata(t *testing.T) {
+	memory := NewMemory()
+	memory.AddBill(sunlight.Bill{BillID: "hr3590-111", ShortTitle: "ACA"})
+	memory.AddNomination(sunlight.Nomination{NominationID: "PN45-115"})
+	upstream := &countingSource{VoteSource: memory}
+	cached := NewCachedMetadata(upstream)
+
+	for i := 0; i < 3; i++ {
+		bill, err := cached.GetBill("hr3590-111")
+		if err != nil || bill.ShortTitle != "ACA" {
+			t.Errorf("GetBill returned %+v, %v", bill, err)
+		}
+		_, err = cached.GetBill("hr1-111")
+		if err != sunlight.ErrBillNotFound {
+			t.Errorf("GetBill of a missing bill returned %v", err)
+		}
+		nomination, err := cached.GetNomination("PN45-115")
+		if err != nil || nomination.NominationID != "PN45-115" {
+			t.Errorf("GetNomination returned %+v, %v", nomination, err)
+		}
+	}
+	if upstream.bills != 2 || upstream.nominations != 1 {
+		t.Errorf(
+			"Expected 2 bill and 1 nomination lookups upstream, got %d and %d",
+			upstream.bills,
+			upstream.nominations,
+		)
+	}
+
+	// Failures aren't held on to.
+	upstream.fail = true
+	for i := 0; i < 2; i++ {
+		_, err := cached.GetNomination("PN1-115")
+		if err != errUpstream {
+			t.Errorf("GetNomination returned %v, expected the upstream error", err)
+		}
+	}
+	if upstream.nominations != 3 {
+		t.Errorf("Expected failed lookups to be retried, got %d lookups", upstream.nominations)
+	}
+}
+
+func TestCachedMetadataEviction(t *testing.T) {
+	upstream := &countingSource{VoteSource: NewMemory()}
+	cached := NewCachedMetadata(upstream)
+
+	for i := 0; i < maxCachedMetadata+10; i++ {
+		cached.GetBill(fmt.Sprintf("hr%d-115", i))
+	}
+	if len(cached.entries) != maxCachedMetadata {
+		t.Errorf(
+			"Holding %d entries, expected at most %d",
+			len(cached.entries),
+			maxCachedMetadata,
+		)
+	}
+}
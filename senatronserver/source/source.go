@@ -0,0 +1,51 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"github.com/senatron/senatron/senatronserver/sunlight"
+)
+
+// VoteSource is a provider of votes, the legislators who cast them and
+// the bills and nominations they were cast on.  Implementations should
+// return sunlight.ErrVoteNotFound, sunlight.ErrLegislatorNotFound,
+// sunlight.ErrBillNotFound and sunlight.ErrNominationNotFound when
+// asked for something that doesn't exist, so callers can tell a
+// missing record from a failed lookup.
+type VoteSource interface {
+	GetVote(rollID string) (sunlight.Vote, error)
+	ListVotes(query sunlight.VoteQuery) ([]sunlight.Vote, error)
+	GetLegislator(bioguideID string) (sunlight.Legislator, error)
+	GetBill(billID string) (sunlight.Bill, error)
+	GetNomination(nominationID string) (sunlight.Nomination, error)
+}
+
+// VoteIterator is implemented by sources that keep their votes at hand
+// and can walk through every vote matching a query in one go, rather
+// than a page at a time.  EachVote passes the matching votes to fn,
+// most recent first, until fn returns false.  The query's pagination
+// is ignored.
+type VoteIterator interface {
+	EachVote(query sunlight.VoteQuery, fn func(sunlight.Vote) bool) error
+}
+
+var _ VoteSource = (*sunlight.Client)(nil)
+var _ VoteSource = (*Memory)(nil)
+var _ VoteIterator = (*Memory)(nil)
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/senatron/senatron/senatronserver/context"
+	"github.com/senatron/senatron/senatronserver/propublica"
+	"github.com/senatron/senatron/senatronserver/senategov"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/store"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+)
+
+// initVoteSource sets up the vote provider selected in the config and
+// stores it in the global context.  Remote providers are wrapped so
+// that the bills and nominations votes are taken on are held in
+// memory, and if a store is configured, the provider is wrapped so
+// that the store is consulted first.
+func initVoteSource(
+	globalContext *context.GlobalContext,
+	config *Config,
+) error {
+	var voteStore *store.Store
+	if config.Store.Path != "" {
+		var err error
+		voteStore, err = store.Open(config.Store.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch config.Source.Provider {
+	case "store":
+		if voteStore == nil {
+			return errors.New("The store source requires a store path")
+		}
+		globalContext.Votes = voteStore
+		return nil
+
+	case "sunlight":
+		if config.Sunlight.APIKey == "" {
+			return errors.New("The sunlight source requires an API key")
+		}
+		globalContext.Votes = source.NewCachedMetadata(
+			sunlight.New(config.Sunlight.APIKey),
+		)
+
+	case "propublica":
+		if config.ProPublica.APIKey == "" {
+			return errors.New("The propublica source requires an API key")
+		}
+		globalContext.Votes = source.NewCachedMetadata(
+			propublica.New(config.ProPublica.APIKey),
+		)
+
+	case "senategov":
+		if config.SenateGov.Directory == "" {
+			return errors.New("The senategov source requires a directory")
+		}
+		votes, err := senategov.ReadDir(config.SenateGov.Directory)
+		if err != nil {
+			return err
+		}
+
+		memory := source.NewMemory()
+		for _, vote := range votes {
+			memory.AddVote(vote)
+		}
+		globalContext.Votes = memory
+
+	default:
+		return fmt.Errorf("Unknown vote source %q", config.Source.Provider)
+	}
+
+	if voteStore != nil {
+		globalContext.Votes = store.NewCached(voteStore, globalContext.Votes)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package store
+
+import (
+	"errors"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"log"
+	"sync"
+	"time"
+)
+
+var errNotFound = errors.New("store: Not found")
+
+// Cached is a VoteSource that consults a Store before going to an
+// upstream source, and saves anything it fetches from upstream into
+// the Store.  That way historical votes keep being served even when
+// the upstream source is slow or down.  Failing to save something only
+// gets logged, since whatever was fetched is still good.
+type Cached struct {
+	Store    *Store
+	Upstream source.VoteSource
+
+	// listingFailedAt is when upstream last failed to list votes.
+	listingFailedAt time.Time
+	mutex           sync.Mutex
+}
+
+// listingRetryDelay is how long ListVotes sticks to the store after
+// upstream fails to list votes, before trying upstream again.
+const listingRetryDelay = time.Minute
+
+var _ source.VoteSource = (*Cached)(nil)
+
+// NewCached returns a Cached source backed by the given store and
+// upstream source.
+func NewCached(store *Store, upstream source.VoteSource) *Cached {
+	return &Cached{Store: store, Upstream: upstream}
+}
+
+// GetVote returns the vote with the given roll ID from the store if
+// it's there, and from upstream otherwise.  Stored votes without any
+// voters (like the summaries in a senate.gov vote menu) are only used
+// as a last resort if upstream fails.  Stored votes that can't be read
+// back (say, because they were saved by a version that was less picky
+// about vote positions) are logged and replaced with upstream's copy.
+func (c *Cached) GetVote(rollID string) (sunlight.Vote, error) {
+	stored, err := c.Store.GetVote(rollID)
+	if err == nil && len(stored.Voters) > 0 {
+		return stored, nil
+	} else if err != nil && err != sunlight.ErrVoteNotFound {
+		log.Printf("Fetching vote %s again: %v", rollID, err)
+	}
+	haveStored := err == nil
+
+	vote, err := c.Upstream.GetVote(rollID)
+	if err != nil {
+		if haveStored {
+			return stored, nil
+		}
+		return sunlight.Vote{}, err
+	}
+
+	logSaveError("vote", rollID, c.Store.PutVote(vote))
+	return vote, nil
+}
+
+// ListVotes lists votes from upstream, caching any complete votes it
+// gets back, since the store only has the votes that have been fetched
+// before and new ones wouldn't show up otherwise.  If upstream fails
+// it falls back to listing the store, and keeps doing so without
+// waiting on upstream for listingRetryDelay afterwards.
+func (c *Cached) ListVotes(
+	query sunlight.VoteQuery,
+) ([]sunlight.Vote, error) {
+	c.mutex.Lock()
+	upstreamDown := time.Since(c.listingFailedAt) < listingRetryDelay
+	c.mutex.Unlock()
+	if upstreamDown {
+		return c.Store.ListVotes(query)
+	}
+
+	votes, err := c.Upstream.ListVotes(query)
+	if err != nil {
+		log.Printf("Listing votes from the store: %v", err)
+		c.mutex.Lock()
+		c.listingFailedAt = time.Now()
+		c.mutex.Unlock()
+		return c.Store.ListVotes(query)
+	}
+
+	for _, vote := range votes {
+		if len(vote.Voters) == 0 {
+			continue
+		}
+		logSaveError("vote", vote.RollID, c.Store.PutVote(vote))
+	}
+	return votes, nil
+}
+
+// GetLegislator returns the legislator with the given bioguide ID
+// from the store if it's there, and from upstream otherwise.
+func (c *Cached) GetLegislator(
+	bioguideID string,
+) (sunlight.Legislator, error) {
+	legislator, err := c.Store.GetLegislator(bioguideID)
+	if err != sunlight.ErrLegislatorNotFound {
+		return legislator, err
+	}
+
+	legislator, err = c.Upstream.GetLegislator(bioguideID)
+	if err != nil {
+		return legislator, err
+	}
+
+	logSaveError("legislator", bioguideID, c.Store.PutLegislator(legislator))
+	return legislator, nil
+}
+
+// GetBill returns the bill with the given ID from the store if it's
+// there, and from upstream otherwise.
+func (c *Cached) GetBill(billID string) (sunlight.Bill, error) {
+	bill, err := c.Store.GetBill(billID)
+	if err != sunlight.ErrBillNotFound {
+		return bill, err
+	}
+
+	bill, err = c.Upstream.GetBill(billID)
+	if err != nil {
+		return bill, err
+	}
+
+	logSaveError("bill", billID, c.Store.PutBill(bill))
+	return bill, nil
+}
+
+// GetNomination returns the nomination with the given ID from the
+// store if it's there, and from upstream otherwise.
+func (c *Cached) GetNomination(
+	nominationID string,
+) (sunlight.Nomination, error) {
+	nomination, err := c.Store.GetNomination(nominationID)
+	if err != sunlight.ErrNominationNotFound {
+		return nomination, err
+	}
+
+	nomination, err = c.Upstream.GetNomination(nominationID)
+	if err != nil {
+		return nomination, err
+	}
+
+	logSaveError("nomination", nominationID, c.Store.PutNomination(nomination))
+	return nomination, nil
+}
+
+// logSaveError logs a failure to save something into the store.
+func logSaveError(kind, id string, err error) {
+	if err != nil {
+		log.Printf("Failed to save %s %s to the store: %v", kind, id, err)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package store
+
+import (
+	"encoding/json"
+	"github.com/boltdb/bolt"
+	"github.com/senatron/senatron/senatronserver/source"
+	"github.com/senatron/senatron/senatronserver/sunlight"
+	"log"
+	"time"
+)
+
+var (
+	votesBucket       = []byte("votes")
+	legislatorsBucket = []byte("legislators")
+	billsBucket       = []byte("bills")
+	nominationsBucket = []byte("nominations")
+)
+
+// Store persists votes, legislators, bills and nominations to a
+// BoltDB file on disk, each keyed by their own ID.  It implements
+// source.VoteSource, serving only what's been put into it.
+type Store struct {
+	db *bolt.DB
+}
+
+var _ source.VoteSource = (*Store)(nil)
+var _ source.VoteIterator = (*Store)(nil)
+
+// Open opens (or creates) the store at the given path.  Only one
+// process can have a store open at a time.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{
+			votesBucket,
+			legislatorsBucket,
+			billsBucket,
+			nominationsBucket,
+		} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the store's file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// GetVote returns the stored vote with the given roll ID.
+func (s *Store) GetVote(rollID string) (vote sunlight.Vote, err error) {
+	err = s.get(votesBucket, rollID, &vote)
+	if err == errNotFound {
+		err = sunlight.ErrVoteNotFound
+	}
+	return
+}
+
+// PutVote stores a vote, replacing any existing vote with the same
+// roll ID.
+func (s *Store) PutVote(vote sunlight.Vote) error {
+	return s.put(votesBucket, vote.RollID, vote)
+}
+
+// ListVotes returns a page of stored votes matching the query, most
+// recent first.  Malformed votes are logged and skipped.
+func (s *Store) ListVotes(
+	query sunlight.VoteQuery,
+) ([]sunlight.Vote, error) {
+	votes, err := s.allVotes()
+	if err != nil {
+		return nil, err
+	}
+	return source.Select(votes, query), nil
+}
+
+// EachVote passes every stored vote matching the query to fn, most
+// recent first, until fn returns false.  The store is only read once,
+// so walking through all of it doesn't cost a full read per page the
+// way ListVotes does.  Malformed votes are logged and skipped.
+func (s *Store) EachVote(
+	query sunlight.VoteQuery,
+	fn func(sunlight.Vote) bool,
+) error {
+	votes, err := s.allVotes()
+	if err != nil {
+		return err
+	}
+
+	for _, vote := range source.Matching(votes, query) {
+		if !fn(vote) {
+			break
+		}
+	}
+	return nil
+}
+
+// allVotes decodes every stored vote, in no particular order, logging
+// and skipping any malformed ones.
+func (s *Store) allVotes() ([]sunlight.Vote, error) {
+	votes := []sunlight.Vote{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(votesBucket).ForEach(func(k, v []byte) error {
+			vote := sunlight.Vote{}
+			err := json.Unmarshal(v, &vote)
+			if _, ok := err.(*sunlight.MalformedVoteError); ok {
+				log.Printf("Skipping stored vote %s: %v", k, err)
+				return nil
+			} else if err != nil {
+				return err
+			}
+			votes = append(votes, vote)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetLegislator returns the stored legislator with the given bioguide
+// ID.
+func (s *Store) GetLegislator(
+	bioguideID string,
+) (legislator sunlight.Legislator, err error) {
+	err = s.get(legislatorsBucket, bioguideID, &legislator)
+	if err == errNotFound {
+		err = sunlight.ErrLegislatorNotFound
+	}
+	return
+}
+
+// PutLegislator stores a legislator, replacing any existing
+// legislator with the same bioguide ID.
+func (s *Store) PutLegislator(legislator sunlight.Legislator) error {
+	return s.put(legislatorsBucket, legislator.BioguideID, legislator)
+}
+
+// GetBill returns the stored bill with the given ID.
+func (s *Store) GetBill(billID string) (bill sunlight.Bill, err error) {
+	err = s.get(billsBucket, billID, &bill)
+	if err == errNotFound {
+		err = sunlight.ErrBillNotFound
+	}
+	return
+}
+
+// PutBill stores a bill, replacing any existing bill with the same ID.
+func (s *Store) PutBill(bill sunlight.Bill) error {
+	return s.put(billsBucket, bill.BillID, bill)
+}
+
+// GetNomination returns the stored nomination with the given ID.
+func (s *Store) GetNomination(
+	nominationID string,
+) (nomination sunlight.Nomination, err error) {
+	err = s.get(nominationsBucket, nominationID, &nomination)
+	if err == errNotFound {
+		err = sunlight.ErrNominationNotFound
+	}
+	return
+}
+
+// PutNomination stores a nomination, replacing any existing
+// nomination with the same ID.
+func (s *Store) PutNomination(nomination sunlight.Nomination) error {
+	return s.put(nominationsBucket, nomination.NominationID, nomination)
+}
+
+// get decodes the JSON value stored under key in the given bucket
+// into out, or returns errNotFound if there isn't one.
+func (s *Store) get(bucket []byte, key string, out interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(key))
+		if data == nil {
+			return errNotFound
+		}
+		return json.Unmarshal(data, out)
+	})
+}
+
+// put stores value as JSON under key in the given bucket.
+func (s *Store) put(bucket []byte, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), data)
+	})
+}
@@ -20,6 +20,8 @@
 package sunlight
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -28,6 +30,18 @@ import (
 
 const baseURI = "https://congress.api.sunlightfoundation.com"
 
+// Client fetches data from the Sunlight Congress API.  It implements
+// source.VoteSource.
+type Client struct {
+	APIKey string
+}
+
+// New returns a Client that will authenticate with the given API
+// key.
+func New(apiKey string) *Client {
+	return &Client{APIKey: apiKey}
+}
+
 // buildURI constructs a Congress API URI from the given endpoint and
 // query parameters.  Every one of the params must be either a string
 // or a slice of strings (for multi-valued parameters).  Passing any
@@ -76,3 +90,33 @@ func getRequest(
 	request.Header.Set("X-APIKEY", apiKey)
 	return
 }
+
+// get fetches the given endpoint with the given query parameters and
+// decodes the JSON response into out.
+func (c *Client) get(
+	endpoint string,
+	params map[string]interface{},
+	out interface{},
+) error {
+	uri, err := buildURI(endpoint, params)
+	if err != nil {
+		return err
+	}
+
+	request, err := getRequest(c.APIKey, uri)
+	if err != nil {
+		return err
+	}
+
+	response, err := getHTTPClient().Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return errors.New(response.Status)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	return decoder.Decode(out)
+}
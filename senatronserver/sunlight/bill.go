@@ -0,0 +1,135 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"errors"
+	"strings"
+)
+
+// Bill describes a bill or resolution that a vote was taken on.
+type Bill struct {
+	BillID        string   `json:"bill_id"`
+	OfficialTitle string   `json:"official_title"`
+	ShortTitle    string   `json:"short_title"`
+	PopularTitle  string   `json:"popular_title"`
+	Sponsor       Sponsor  `json:"sponsor"`
+	Subjects      []string `json:"subjects"`
+}
+
+// Sponsor identifies the legislator who introduced a bill.
+type Sponsor struct {
+	BioguideID string `json:"bioguide_id"`
+	Name       string `json:"name"`
+	Party      string `json:"party"`
+	State      string `json:"state"`
+}
+
+// Name returns the most recognisable title the bill has, falling back
+// to its ID if it doesn't have any.
+func (b Bill) Name() string {
+	for _, title := range []string{
+		b.PopularTitle,
+		b.ShortTitle,
+		b.OfficialTitle,
+	} {
+		if title != "" {
+			return title
+		}
+	}
+	return b.BillID
+}
+
+// ErrBillNotFound signals a failure in looking up a given bill,
+// probably because no bill by the given ID exists.
+var ErrBillNotFound = errors.New("No results for that bill ID")
+
+var billFields = strings.Join(
+	[]string{
+		"bill_id",
+		"official_title",
+		"short_title",
+		"popular_title",
+		"sponsor",
+		"sponsor_id",
+		"keywords",
+	},
+	",",
+)
+
+// GetBill returns information about the bill with the given ID (like
+// "hr3590-111"), or returns an error if anything goes wrong.
+func (c *Client) GetBill(billID string) (bill Bill, err error) {
+	resultContainer := struct {
+		Results []struct {
+			BillID        string `json:"bill_id"`
+			OfficialTitle string `json:"official_title"`
+			ShortTitle    string `json:"short_title"`
+			PopularTitle  string `json:"popular_title"`
+			SponsorID     string `json:"sponsor_id"`
+			Sponsor       struct {
+				FirstName string `json:"first_name"`
+				LastName  string `json:"last_name"`
+				Party     string `json:"party"`
+				State     string `json:"state"`
+			} `json:"sponsor"`
+			Keywords []string `json:"keywords"`
+		} `json:"results"`
+		Count int `json:"count"`
+	}{}
+
+	err = c.get(
+		"bills",
+		map[string]interface{}{
+			"bill_id": billID,
+			"fields":  billFields,
+		},
+		&resultContainer,
+	)
+	if err != nil {
+		return
+	}
+
+	if resultContainer.Count == 0 {
+		err = ErrBillNotFound
+		return
+	}
+
+	result := resultContainer.Results[0]
+	bill = Bill{
+		BillID:        result.BillID,
+		OfficialTitle: result.OfficialTitle,
+		ShortTitle:    result.ShortTitle,
+		PopularTitle:  result.PopularTitle,
+		Sponsor: Sponsor{
+			BioguideID: result.SponsorID,
+			Name: strings.TrimSpace(
+				result.Sponsor.FirstName + " " + result.Sponsor.LastName,
+			),
+			Party: result.Sponsor.Party,
+			State: result.Sponsor.State,
+		},
+		Subjects: result.Keywords,
+	}
+	if bill.Subjects == nil {
+		bill.Subjects = []string{}
+	}
+	return
+}
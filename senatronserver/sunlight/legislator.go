@@ -0,0 +1,107 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"errors"
+	"strings"
+)
+
+// Legislator describes a member of Congress.
+type Legislator struct {
+	BioguideID string `json:"bioguide_id"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	State      string `json:"state"`
+	Party      string `json:"party"`
+	Chamber    string `json:"chamber"`
+
+	// TermStart and TermEnd bound the legislator's current (or most
+	// recent) term, as YYYY-MM-DD dates.
+	TermStart string `json:"term_start"`
+	TermEnd   string `json:"term_end"`
+
+	// Terms lists every term the legislator has served, oldest
+	// first, if the source provides them.
+	Terms []Term `json:"terms"`
+}
+
+// Term describes a single term served by a legislator.
+type Term struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Chamber string `json:"chamber"`
+	State   string `json:"state"`
+	Party   string `json:"party"`
+}
+
+// legislatorFields lists the fields GetLegislator asks the API for.
+// Terms aren't returned unless they're asked for explicitly.
+var legislatorFields = strings.Join(
+	[]string{
+		"bioguide_id",
+		"first_name",
+		"last_name",
+		"state",
+		"party",
+		"chamber",
+		"term_start",
+		"term_end",
+		"terms",
+	},
+	",",
+)
+
+// ErrLegislatorNotFound signals a failure in looking up a given
+// legislator, probably because no legislator by the given bioguide
+// ID exists.
+var ErrLegislatorNotFound = errors.New("No results for that bioguide ID")
+
+// GetLegislator returns information about the legislator with the
+// given bioguide ID, or returns an error if anything goes wrong.
+func (c *Client) GetLegislator(
+	bioguideID string,
+) (legislator Legislator, err error) {
+	resultContainer := struct {
+		Results []Legislator `json:"results"`
+		Count   int          `json:"count"`
+	}{}
+
+	err = c.get(
+		"legislators",
+		map[string]interface{}{
+			"bioguide_id":     bioguideID,
+			"all_legislators": "true",
+			"fields":          legislatorFields,
+		},
+		&resultContainer,
+	)
+	if err != nil {
+		return
+	}
+
+	if resultContainer.Count == 0 {
+		err = ErrLegislatorNotFound
+		return
+	}
+
+	legislator = resultContainer.Results[0]
+	return
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"errors"
+	"strings"
+)
+
+// Nomination describes a presidential nomination that a vote was
+// taken on.
+type Nomination struct {
+	NominationID string `json:"nomination_id"`
+
+	// Organization is the agency or court the nominees would serve
+	// in.
+	Organization string `json:"organization"`
+
+	// Description is the nomination as the source summarises it,
+	// like "Jane Doe, of Ohio, to be Secretary of Labor", if it has
+	// one.
+	Description string `json:"description"`
+
+	Nominees []Nominee `json:"nominees"`
+}
+
+// Nominee describes one of the people named in a nomination.
+type Nominee struct {
+	Name     string `json:"name"`
+	Position string `json:"position"`
+	State    string `json:"state"`
+}
+
+// Name describes the nomination in a few words, falling back to its
+// ID if there's nothing better.
+func (n Nomination) Name() string {
+	if n.Description != "" {
+		return n.Description
+	}
+
+	names := make([]string, 0, len(n.Nominees))
+	for _, nominee := range n.Nominees {
+		if nominee.Position == "" {
+			names = append(names, nominee.Name)
+		} else {
+			names = append(names, nominee.Name+", "+nominee.Position)
+		}
+	}
+	if len(names) == 0 {
+		return n.NominationID
+	}
+	return strings.Join(names, "; ")
+}
+
+// ErrNominationNotFound signals a failure in looking up a given
+// nomination, probably because no nomination by the given ID exists.
+var ErrNominationNotFound = errors.New("No results for that nomination ID")
+
+// GetNomination returns information about the nomination with the
+// given ID (like "PN1234-113"), or returns an error if anything goes
+// wrong.
+func (c *Client) GetNomination(
+	nominationID string,
+) (nomination Nomination, err error) {
+	resultContainer := struct {
+		Results []Nomination `json:"results"`
+		Count   int          `json:"count"`
+	}{}
+
+	err = c.get(
+		"nominations",
+		map[string]interface{}{
+			"nomination_id": nominationID,
+			"fields":        "nomination_id,organization,nominees",
+		},
+		&resultContainer,
+	)
+	if err != nil {
+		return
+	}
+
+	if resultContainer.Count == 0 {
+		err = ErrNominationNotFound
+		return
+	}
+
+	nomination = resultContainer.Results[0]
+	return
+}
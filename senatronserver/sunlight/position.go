@@ -0,0 +1,120 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Position is a normalized vote position.  Sources record the same
+// positions in different ways ("Yea", "Aye", "Yes", "Guilty" and so
+// on), which ParsePosition folds down into these.
+type Position string
+
+const (
+	// Yes covers every position in favour of the question, including
+	// guilty verdicts on impeachment.
+	Yes Position = "yes"
+
+	// No covers every position against the question, including not
+	// guilty verdicts on impeachment.
+	No Position = "no"
+
+	// Present is recorded by legislators who are there, but decline
+	// to vote either way.
+	Present Position = "present"
+
+	// NotVoting is recorded by legislators who are absent.
+	NotVoting Position = "not-voting"
+)
+
+// Positions lists every position.
+var Positions = []Position{Yes, No, Present, NotVoting}
+
+// rawPositions maps the positions sources use (in lower case) onto
+// normalized ones.
+var rawPositions = map[string]Position{
+	"yes":        Yes,
+	"yea":        Yes,
+	"aye":        Yes,
+	"guilty":     Yes,
+	"no":         No,
+	"nay":        No,
+	"not guilty": No,
+	"present":    Present,
+	"not voting": NotVoting,
+}
+
+// positionLabels are how each position is presented to people.
+var positionLabels = map[Position]string{
+	Yes:       "Yea",
+	No:        "Nay",
+	Present:   "Present",
+	NotVoting: "Not Voting",
+}
+
+// ParsePosition normalizes a position as recorded by a source, or one
+// that's already normalized.  Present positions with qualifications
+// (like "Present, Giving Live Pair") count as Present.  It returns a
+// *MalformedVoteError for anything else.
+func ParsePosition(raw string) (Position, error) {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if position, ok := rawPositions[key]; ok {
+		return position, nil
+	}
+	for _, position := range Positions {
+		if key == string(position) {
+			return position, nil
+		}
+	}
+	if strings.HasPrefix(key, "present") {
+		return Present, nil
+	}
+	return "", &MalformedVoteError{Field: "vote", Value: raw}
+}
+
+// Cast reports whether the position is a vote either way on the
+// question.
+func (p Position) Cast() bool {
+	return p == Yes || p == No
+}
+
+// String returns the position as it's usually presented, like "Yea"
+// for Yes.
+func (p Position) String() string {
+	if label, ok := positionLabels[p]; ok {
+		return label
+	}
+	return strings.Title(string(p))
+}
+
+// UnmarshalJSON reads and normalizes a position in any form
+// ParsePosition accepts.
+func (p *Position) UnmarshalJSON(data []byte) error {
+	var raw string
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*p, err = ParsePosition(raw)
+	return err
+}
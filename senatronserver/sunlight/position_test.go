@@ -0,0 +1,89 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var positionTests = []struct {
+	raw      string
+	expected Position
+}{
+	{"Yea", Yes},
+	{"Aye", Yes},
+	{" yes ", Yes},
+	{"Guilty", Yes},
+	{"Nay", No},
+	{"NO", No},
+	{"Not Guilty", No},
+	{"Present", Present},
+	{"Present, Giving Live Pair", Present},
+	{"Not Voting", NotVoting},
+	{"not-voting", NotVoting},
+}
+
+func TestParsePosition(t *testing.T) {
+	for _, test := range positionTests {
+		position, err := ParsePosition(test.raw)
+		if err != nil {
+			t.Errorf("ParsePosition(%q) failed: %v", test.raw, err)
+		} else if position != test.expected {
+			t.Errorf(
+				"ParsePosition(%q) returned %q, expected %q",
+				test.raw,
+				position,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestParsePositionMalformed(t *testing.T) {
+	// Speaker elections are voted by name.
+	for _, raw := range []string{"", "Pelosi", "yeas"} {
+		_, err := ParsePosition(raw)
+		malformed, ok := err.(*MalformedVoteError)
+		if !ok {
+			t.Errorf("ParsePosition(%q) returned %v, expected malformed", raw, err)
+			continue
+		}
+		if malformed.Field != "vote" || malformed.Value != raw {
+			t.Errorf("ParsePosition(%q) returned %+v", raw, malformed)
+		}
+	}
+}
+
+func TestPositionUnmarshalJSON(t *testing.T) {
+	var positions []Position
+	err := json.Unmarshal([]byte(`["Yea", "Not Voting"]`), &positions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(positions) != 2 || positions[0] != Yes || positions[1] != NotVoting {
+		t.Errorf("Got positions %v", positions)
+	}
+
+	err = json.Unmarshal([]byte(`["Ryan"]`), &positions)
+	if _, ok := err.(*MalformedVoteError); !ok {
+		t.Errorf("Got %v, expected a *MalformedVoteError", err)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VoteQuery narrows down a listing of votes.  Zero-valued fields
+// don't filter anything.
+type VoteQuery struct {
+	Congress int
+	Session  int
+	Chamber  string
+
+	// Since and Until bound the time the vote was taken, inclusively.
+	Since time.Time
+	Until time.Time
+
+	BillID       string
+	NominationID string
+
+	// Result matches the vote's result exactly (ignoring case), and
+	// Question matches any vote whose question contains it.
+	Result   string
+	Question string
+
+	// Voter matches votes that the legislator with the given bioguide
+	// ID took part in.
+	Voter string
+
+	// Page is the 1-indexed page of results to return, and PerPage
+	// the number of results on each page.  Zero values fall back to
+	// the first page and DefaultPerPage.
+	Page    int
+	PerPage int
+}
+
+// DefaultPerPage is the number of votes returned per page of a
+// listing when a VoteQuery doesn't specify one.
+const DefaultPerPage = 20
+
+// Pagination returns the query's page and page size, with defaults
+// filled in.
+func (q VoteQuery) Pagination() (page, perPage int) {
+	page, perPage = q.Page, q.PerPage
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+	return
+}
+
+// Matches reports whether a vote passes all of the query's filters.
+// It ignores pagination.
+func (q VoteQuery) Matches(vote Vote) bool {
+	if q.Congress != 0 && vote.Congress != q.Congress {
+		return false
+	}
+	if q.Session != 0 && vote.Session != q.Session {
+		return false
+	}
+	if q.Chamber != "" && vote.Chamber != q.Chamber {
+		return false
+	}
+
+	if !q.Since.IsZero() && vote.VotedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && vote.VotedAt.After(q.Until) {
+		return false
+	}
+
+	if q.BillID != "" && vote.BillID != q.BillID {
+		return false
+	}
+	if q.NominationID != "" && vote.NominationID != q.NominationID {
+		return false
+	}
+	if q.Result != "" && !strings.EqualFold(vote.Result, q.Result) {
+		return false
+	}
+	if q.Question != "" && !strings.Contains(
+		strings.ToLower(vote.Question),
+		strings.ToLower(q.Question),
+	) {
+		return false
+	}
+	if q.Voter != "" && !hasVoter(vote, q.Voter) {
+		return false
+	}
+
+	return true
+}
+
+// hasVoter reports whether the legislator with the given bioguide ID
+// is among a vote's voters.  Voters aren't always keyed by bioguide
+// ID (senate.gov records use LIS IDs), so every one is checked.
+func hasVoter(vote Vote, bioguideID string) bool {
+	if _, ok := vote.Voters[bioguideID]; ok {
+		return true
+	}
+	for _, v := range vote.Voters {
+		if v.Info.BioguideID == bioguideID {
+			return true
+		}
+	}
+	return false
+}
+
+// paramsInto adds the query's parameters to a Congress API parameter
+// map.  The API has no notion of sessions, so a session can only be
+// filtered on along with a congress, which together pick out a year.
+func (q VoteQuery) paramsInto(params map[string]interface{}) error {
+	if q.Congress != 0 {
+		params["congress"] = strconv.Itoa(q.Congress)
+	}
+	if q.Session != 0 {
+		if q.Congress == 0 {
+			return errors.New(
+				"sunlight: Can't filter on session without a congress",
+			)
+		}
+		params["year"] = strconv.Itoa(1789 + 2*(q.Congress-1) + q.Session - 1)
+	}
+	if q.Chamber != "" {
+		params["chamber"] = q.Chamber
+	}
+	if !q.Since.IsZero() {
+		params["voted_at__gte"] = q.Since.UTC().Format(time.RFC3339)
+	}
+	if !q.Until.IsZero() {
+		params["voted_at__lte"] = q.Until.UTC().Format(time.RFC3339)
+	}
+	if q.BillID != "" {
+		params["bill_id"] = q.BillID
+	}
+	if q.NominationID != "" {
+		params["nomination_id"] = q.NominationID
+	}
+	if q.Result != "" {
+		params["result"] = q.Result
+	}
+	if q.Question != "" {
+		params["query"] = q.Question
+	}
+	if q.Voter != "" {
+		params["voter_ids."+q.Voter+"__exists"] = "true"
+	}
+
+	page, perPage := q.Pagination()
+	params["page"] = strconv.Itoa(page)
+	params["per_page"] = strconv.Itoa(perPage)
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Chamber names, as used in roll IDs and legislator records.
+const (
+	Senate = "senate"
+	House  = "house"
+)
+
+var rollIDPattern = regexp.MustCompile(`^([hs])(\d+)-(\d{4})$`)
+
+// ParseRollID splits a roll ID like "s396-2009" into its chamber,
+// roll call number and year.
+func ParseRollID(
+	rollID string,
+) (chamber string, number, year int, err error) {
+	matches := rollIDPattern.FindStringSubmatch(rollID)
+	if matches == nil {
+		err = fmt.Errorf("sunlight: Malformed roll ID %q", rollID)
+		return
+	}
+
+	chamber = Senate
+	if matches[1] == "h" {
+		chamber = House
+	}
+	number, _ = strconv.Atoi(matches[2])
+	year, _ = strconv.Atoi(matches[3])
+	return
+}
+
+// RollID builds a roll ID from its chamber, roll call number and
+// year.
+func RollID(chamber string, number, year int) string {
+	return fmt.Sprintf("%c%d-%d", chamber[0], number, year)
+}
+
+// CongressForYear returns the number and session of the Congress
+// sitting for most of the given year.  This ignores the few days at
+// the start of odd years that still belong to the previous Congress.
+func CongressForYear(year int) (congress, session int) {
+	congress = (year-1789)/2 + 1
+	session = 2 - year%2
+	return
+}
@@ -22,100 +22,227 @@ package sunlight
 import (
 	"encoding/json"
 	"errors"
-	"net/http"
+	"fmt"
+	"log"
 	"strings"
+	"time"
 )
 
 // Vote describes the outcome of a vote, both in terms of senate and
 // popular vote, as well as the senators and their votes and basic
 // info like the bill ID, date and so on.
 type Vote struct {
-	RollID   string `json:"roll_id"`
-	VotedAt  string `json:"voted_at"`
-	RollType string `json:"roll_type"`
-	Question string `json:"question"`
-	Required string `json:"required"`
-	Result   string `json:"result"`
+	RollID   string    `json:"roll_id"`
+	Chamber  string    `json:"chamber"`
+	Congress int       `json:"congress"`
+	Session  int       `json:"session"`
+	VotedAt  time.Time `json:"voted_at"`
+	RollType string    `json:"roll_type"`
+	Question string    `json:"question"`
+	Required string    `json:"required"`
+	Result   string    `json:"result"`
 
 	// At most one of BillID or NominationID will be non-empty.
 	BillID       string `json:"bill_id"`
 	NominationID string `json:"nomination_id"`
 
-	Voters map[string]struct {
-		Vote string `json:"vote"`
-		Info struct {
-			BioguideID string `json:"bioguide_id"`
-			State      string `json:"state"`
-			Party      string `json:"party"`
-		} `json:"voter"`
-	} `json:"voters"`
+	// Voters is keyed by the voter's bioguide ID (or LIS ID, for
+	// votes imported from senate.gov).
+	Voters map[string]Voter `json:"voters"`
+}
+
+// Eastern is the timezone Congress keeps time in, and so the one that
+// sources like senate.gov and ProPublica report vote times in.  It's
+// UTC if the timezone database isn't available.
+var Eastern = func() *time.Location {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}()
+
+// Voter records how a single legislator voted.
+type Voter struct {
+	Vote Position  `json:"vote"`
+	Info VoterInfo `json:"voter"`
+}
+
+// VoterInfo identifies the legislator behind a Voter.
+type VoterInfo struct {
+	BioguideID string `json:"bioguide_id"`
+	State      string `json:"state"`
+	Party      string `json:"party"`
+
+	// LISID is the Senate's own member ID, which is all that
+	// senate.gov vote records identify senators by.
+	LISID string `json:"lis_id,omitempty"`
+
+	// District is the congressional district a representative was
+	// elected from, with 0 for at-large seats.  It's always 0 for
+	// senators.
+	District int `json:"district,omitempty"`
+}
+
+// MalformedVoteError signals a vote record that couldn't be made sense
+// of, naming the field at fault and its value.
+type MalformedVoteError struct {
+	RollID string
+	Field  string
+	Value  string
+}
+
+func (e *MalformedVoteError) Error() string {
+	if e.RollID == "" {
+		return fmt.Sprintf("Malformed %s %q", e.Field, e.Value)
+	}
+	return fmt.Sprintf(
+		"Malformed %s %q on vote %s",
+		e.Field,
+		e.Value,
+		e.RollID,
+	)
+}
+
+// UnmarshalJSON reads a vote in the Congress API's format (which is
+// also how votes are stored), returning a *MalformedVoteError if it's
+// missing a roll ID or has a bad timestamp or vote position.
+func (v *Vote) UnmarshalJSON(data []byte) error {
+	type plainVote Vote
+	raw := struct {
+		*plainVote
+		VotedAt string `json:"voted_at"`
+	}{plainVote: (*plainVote)(v)}
+
+	err := json.Unmarshal(data, &raw)
+	if malformed, ok := err.(*MalformedVoteError); ok {
+		malformed.RollID = v.RollID
+		return malformed
+	} else if err != nil {
+		return err
+	}
+
+	if v.RollID == "" {
+		return &MalformedVoteError{Field: "roll_id"}
+	}
+
+	v.VotedAt, err = time.Parse(time.RFC3339, raw.VotedAt)
+	if err != nil {
+		return &MalformedVoteError{
+			RollID: v.RollID,
+			Field:  "voted_at",
+			Value:  raw.VotedAt,
+		}
+	}
+	return nil
 }
 
 // ErrVoteNotFound signals a failure in looking up a given vote,
 // probably because no vote by the given roll ID exists.
 var ErrVoteNotFound = errors.New("No results for that roll ID")
 
+var voteFields = strings.Join(
+	[]string{
+		"roll_id",
+		"chamber",
+		"congress",
+		"year",
+		"bill_id",
+		"nomination_id",
+		"roll_type",
+		"question",
+		"required",
+		"result",
+		"voted_at",
+		"voters",
+	},
+	",",
+)
+
 // GetVote returns information about the given rollID, or returns an
 // error if anything goes wrong.
-func GetVote(apiKey string, rollID string) (vote Vote, err error) {
-	fields := strings.Join(
-		[]string{
-			"roll_id",
-			"bill_id",
-			"nomination_id",
-			"roll_type",
-			"question",
-			"required",
-			"result",
-			"voted_at",
-			"voters",
-		},
-		",",
-	)
-	uri, err := buildURI(
-		"votes",
+func (c *Client) GetVote(rollID string) (vote Vote, err error) {
+	votes, count, err := c.getVotes(
 		map[string]interface{}{
 			"roll_id": rollID,
-			"fields":  fields,
 		},
+		false,
 	)
 	if err != nil {
 		return
 	}
 
-	request, err := getRequest(apiKey, uri)
-	client := getHTTPClient()
-
-	response, err := client.Do(request)
-	if err != nil {
+	if count == 0 {
+		err = ErrVoteNotFound
 		return
+	} else if count != 1 {
+		// This should never happen
+		err = errors.New("More than one vote found for a single roll ID")
+		return
+	}
+
+	vote = votes[0]
+	return
+}
+
+// ListVotes returns a page of votes matching the given query, most
+// recent first.
+func (c *Client) ListVotes(query VoteQuery) (votes []Vote, err error) {
+	params := map[string]interface{}{
+		"order": "voted_at__desc",
 	}
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		err = errors.New(response.Status)
+	err = query.paramsInto(params)
+	if err != nil {
 		return
 	}
 
+	votes, _, err = c.getVotes(params, true)
+	return
+}
+
+// getVotes fetches the votes endpoint with the given parameters,
+// returning the votes along with the total count of matching votes.
+// If skipMalformed is set, votes that fail with a *MalformedVoteError
+// are logged and left out rather than failing the whole listing.
+func (c *Client) getVotes(
+	params map[string]interface{},
+	skipMalformed bool,
+) (votes []Vote, count int, err error) {
+	params["fields"] = voteFields
+
 	resultContainer := struct {
-		Results []Vote `json:"results"`
-		Count   int    `json:"count"`
+		Results []json.RawMessage `json:"results"`
+		Count   int               `json:"count"`
 	}{}
 
-	decoder := json.NewDecoder(response.Body)
-	err = decoder.Decode(&resultContainer)
+	err = c.get("votes", params, &resultContainer)
 	if err != nil {
 		return
 	}
 
-	if resultContainer.Count == 0 {
-		err = ErrVoteNotFound
-		return
-	} else if resultContainer.Count != 1 {
-		// This should never happen
-		err = errors.New("More than one vote found for a single roll ID")
-		return
-	}
+	// The Congress API doesn't report sessions, so we work them out
+	// from the year, which has to be decoded separately since Vote
+	// does its own decoding.
+	votes = make([]Vote, 0, len(resultContainer.Results))
+	for _, result := range resultContainer.Results {
+		var vote Vote
+		err = json.Unmarshal(result, &vote)
+		if _, ok := err.(*MalformedVoteError); ok && skipMalformed {
+			log.Printf("Skipping vote: %v", err)
+			continue
+		} else if err != nil {
+			return
+		}
 
-	vote = resultContainer.Results[0]
-	return
+		year := struct {
+			Year int `json:"year"`
+		}{}
+		err = json.Unmarshal(result, &year)
+		if err != nil {
+			return
+		}
+		_, vote.Session = CongressForYear(year.Year)
+		votes = append(votes, vote)
+	}
+	return votes, resultContainer.Count, nil
 }
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2016, Robert Bieber
+ *
+ * This file is part of senatron.
+ *
+ * senatron is free software: you can redistribute it and/or modify it
+ * under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * senatron is distributed in the hope that it will be useful,
+ * WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with senatron.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sunlight
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestVoteUnmarshalJSON(t *testing.T) {
+	var vote Vote
+	err := json.Unmarshal([]byte(`{
+		"roll_id": "s17-2017",
+		"chamber": "senate",
+		"congress": 115,
+		"voted_at": "2017-01-20T16:30:00-05:00",
+		"voters": {
+			"B001230": {
+				"vote": "Yea",
+				"voter": {"bioguide_id": "B001230", "state": "WI", "party": "D"}
+			}
+		}
+	}`), &vote)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vote.RollID != "s17-2017" || vote.Congress != 115 {
+		t.Errorf("Wrong vote details: %+v", vote)
+	}
+	votedAt := time.Date(2017, 1, 20, 21, 30, 0, 0, time.UTC)
+	if !vote.VotedAt.Equal(votedAt) {
+		t.Errorf("Voted at %v, expected %v", vote.VotedAt, votedAt)
+	}
+	if voter := vote.Voters["B001230"]; voter.Vote != Yes ||
+		voter.Info.State != "WI" {
+		t.Errorf("Wrong voter: %+v", voter)
+	}
+}
+
+var malformedVoteTests = []struct {
+	data     string
+	expected MalformedVoteError
+}{
+	{
+		`{"voted_at": "2017-01-20T16:30:00-05:00"}`,
+		MalformedVoteError{Field: "roll_id"},
+	},
+	{
+		`{"roll_id": "s17-2017", "voted_at": "January 20, 2017"}`,
+		MalformedVoteError{
+			RollID: "s17-2017",
+			Field:  "voted_at",
+			Value:  "January 20, 2017",
+		},
+	},
+	{
+		`{"roll_id": "s17-2017"}`,
+		MalformedVoteError{RollID: "s17-2017", Field: "voted_at"},
+	},
+	{
+		`{
+			"roll_id": "h1-2017",
+			"voted_at": "2017-01-03T13:58:00-05:00",
+			"voters": {"R000570": {"vote": "Ryan"}}
+		}`,
+		MalformedVoteError{RollID: "h1-2017", Field: "vote", Value: "Ryan"},
+	},
+}
+
+func TestVoteUnmarshalJSONMalformed(t *testing.T) {
+	for _, test := range malformedVoteTests {
+		var vote Vote
+		err := json.Unmarshal([]byte(test.data), &vote)
+		malformed, ok := err.(*MalformedVoteError)
+		if !ok {
+			t.Errorf("Decoding %s returned %v, expected malformed", test.data, err)
+			continue
+		}
+		if *malformed != test.expected {
+			t.Errorf(
+				"Decoding %s returned %+v, expected %+v",
+				test.data,
+				*malformed,
+				test.expected,
+			)
+		}
+	}
+}
@@ -41,5 +41,26 @@ func initTemplates(
 		return err
 	}
 
+	globalContext.Templates.Vote, err = template.ParseFiles(
+		staticPath("vote.got"),
+	)
+	if err != nil {
+		return err
+	}
+
+	globalContext.Templates.Legislator, err = template.ParseFiles(
+		staticPath("legislator.got"),
+	)
+	if err != nil {
+		return err
+	}
+
+	globalContext.Templates.Bill, err = template.ParseFiles(
+		staticPath("bill.got"),
+	)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }